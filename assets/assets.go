@@ -0,0 +1,76 @@
+// Package assets contiene los iconos de MultiTool embebidos en el binario, para
+// que no dependan del directorio desde el que se arranca.
+//
+// Los iconos de las herramientas son SVG de un solo color que se dibujan con el
+// color del texto del tema, así que siguen al tema claro u oscuro. Se piden por
+// su nombre de icono de tema (IconPDF...), de modo que un tema puede sustituirlos
+// igual que los iconos de Fyne (ver la sección "icons" de los archivos de tema).
+package assets
+
+import (
+	"embed"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/theme"
+)
+
+//go:embed icon.png *.svg
+var files embed.FS
+
+// Nombres de tema de los iconos de MultiTool.
+const (
+	IconPDF     fyne.ThemeIconName = "multitool.pdf"
+	IconNetwork fyne.ThemeIconName = "multitool.network"
+)
+
+// AppIcon es el icono de la aplicación, para las ventanas y la bandeja del sistema.
+var AppIcon = load("icon.png")
+
+// defaults son los iconos incluidos, por nombre de tema.
+var defaults = map[fyne.ThemeIconName]fyne.Resource{
+	IconPDF:     theme.NewThemedResource(load("pdf.svg")),
+	IconNetwork: theme.NewThemedResource(load("change.svg")),
+}
+
+// load lee un archivo embebido. Los nombres son fijos y go:embed comprueba al
+// compilar que existen, así que un error aquí es un fallo de programación.
+func load(name string) fyne.Resource {
+	data, err := files.ReadFile(name)
+	if err != nil {
+		panic(err)
+	}
+	return fyne.NewStaticResource(name, data)
+}
+
+// Icon devuelve el icono name. Puede llamarse antes de que exista la aplicación
+// (desde el init() de una herramienta, por ejemplo): el recurso se resuelve cada
+// vez que se dibuja, con el del tema actual si lo sustituye o, si no, con el
+// incluido.
+func Icon(name fyne.ThemeIconName) fyne.Resource {
+	return &themedIcon{name: name}
+}
+
+// Default devuelve el icono incluido name, sin las sustituciones del tema, o nil
+// si no existe.
+func Default(name fyne.ThemeIconName) fyne.Resource {
+	return defaults[name]
+}
+
+// themedIcon es el recurso que devuelve Icon. Su contenido ya tiene el color del
+// tema, así que no implementa fyne.ThemedResource: Fyne volvería a colorear los
+// iconos que aporte un tema.
+type themedIcon struct {
+	name fyne.ThemeIconName
+}
+
+func (i *themedIcon) current() fyne.Resource {
+	if app := fyne.CurrentApp(); app != nil {
+		if res := app.Settings().Theme().Icon(i.name); res != nil {
+			return res
+		}
+	}
+	return defaults[i.name]
+}
+
+func (i *themedIcon) Name() string    { return i.current().Name() }
+func (i *themedIcon) Content() []byte { return i.current().Content() }
@@ -0,0 +1,337 @@
+// Package automation sirve una API HTTP local para que otros programas controlen
+// un MultiTool en marcha: listar y aplicar perfiles de red, abrir PDFs en el
+// PDF Merger, fusionar...
+//
+// Es opcional (se activa en los ajustes) y solo escucha en local: por defecto en
+// un socket Unix dentro del directorio de configuración y, si se configura una
+// dirección, en un puerto de loopback. Cada petición debe llevar la cabecera
+// "Authorization: Bearer <token>" con el token del archivo api-token, que se
+// genera la primera vez. Las rutas las aportan las herramientas en
+// tools.ToolDescriptor.Endpoints y las respuestas son JSON.
+package automation
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"embed"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Lec7ral/MultiTool/config"
+	"github.com/Lec7ral/MultiTool/i18n"
+	"github.com/Lec7ral/MultiTool/settings"
+	"github.com/Lec7ral/MultiTool/tools"
+)
+
+//go:embed locales
+var locales embed.FS
+
+func init() {
+	if err := i18n.AddCatalogFS(locales, "locales"); err != nil {
+		slog.Error("failed to load automation translations", "err", err)
+	}
+}
+
+// Sección y claves de los ajustes de la API.
+const (
+	Section        = "automation"
+	SettingEnabled = "enabled"
+	SettingAddress = "address"
+)
+
+// TokenPath es el archivo con el token que deben presentar los clientes.
+var TokenPath = config.Path("api-token")
+
+// socketPath es el socket en el que se escucha si no se configura una dirección.
+var socketPath = config.Path("api.sock")
+
+// Schema devuelve el esquema de los ajustes de la API.
+func Schema() settings.Schema {
+	return settings.Schema{
+		Section: Section,
+		Title:   "Automation",
+		Version: 1,
+		Fields: []settings.Field{
+			{Key: SettingEnabled, Label: "Enable the local automation API", Kind: settings.Bool, Default: false,
+				Description: "Clients authenticate with the token in the api-token file of the configuration directory. Takes effect the next time MultiTool starts."},
+			{Key: SettingAddress, Label: "Loopback address", Kind: settings.String, Default: "",
+				Description: "For example 127.0.0.1:8765. Empty listens on the api.sock socket of the configuration directory."},
+		},
+	}
+}
+
+// maxBodySize es el tamaño máximo del cuerpo de una petición.
+const maxBodySize = 1 << 20
+
+// Host es la aplicación en marcha, tal como la usa la API. Sus métodos se llaman
+// desde la goroutine de cada petición.
+type Host interface {
+	// Open abre paths en la ventana, como si llegaran por la línea de comandos.
+	Open(paths []string)
+
+	// Context devuelve un contexto sin ventana para la herramienta name.
+	Context(name string) *tools.ToolContext
+
+	// Tool muestra la herramienta name en la ventana (abriéndola si hace falta)
+	// y devuelve su instancia y su contexto.
+	Tool(name string) (tools.Tool, *tools.ToolContext, error)
+}
+
+// Server es la API en marcha.
+type Server struct {
+	server *http.Server
+}
+
+// Start arranca la API si está activada en los ajustes; si no, devuelve nil sin
+// error. Las rutas trabajan con la aplicación a través de host.
+func Start(store *settings.Store, host Host) (*Server, error) {
+	section := store.Section(Section)
+	if !section.Bool(SettingEnabled, false) {
+		return nil, nil
+	}
+
+	token, err := loadToken()
+	if err != nil {
+		return nil, fmt.Errorf("automation token: %w", err)
+	}
+
+	mux := http.NewServeMux()
+	routes := append(builtinEndpoints(host), tools.RegisteredEndpoints()...)
+	mux.Handle("GET /v1", handler(func(r *http.Request) (any, error) { return describe(routes), nil }))
+	// http.ServeMux entra en pánico con una ruta repetida; mejor avisar de qué
+	// herramientas la declaran.
+	owners := map[string]string{"GET /v1": "automation"}
+	for _, e := range routes {
+		pattern := e.Method + " " + e.Path
+		owner := e.Tool
+		if owner == "" {
+			owner = "automation"
+		}
+		if previous, ok := owners[pattern]; ok {
+			return nil, fmt.Errorf("duplicate automation route %s (%s and %s)", pattern, previous, owner)
+		}
+		owners[pattern] = owner
+		mux.Handle(pattern, handler(bind(host, e)))
+	}
+
+	listener, err := listen(section.String(SettingAddress, ""))
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{
+		server: &http.Server{
+			Handler:           authorize(token, mux),
+			ReadHeaderTimeout: 10 * time.Second,
+		},
+	}
+	go func() {
+		if err := s.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("automation API stopped", "err", err)
+		}
+	}()
+	slog.Info("automation API listening", "addr", listener.Addr().String())
+	return s, nil
+}
+
+// Close deja de atender peticiones y elimina el socket, si lo hay.
+func (s *Server) Close() error {
+	return s.server.Close()
+}
+
+// listen abre el socket Unix si address está vacía o, si no, el puerto TCP de
+// address, que debe ser de loopback.
+func listen(address string) (net.Listener, error) {
+	if address == "" {
+		// Solo hay una instancia (ver el paquete instance), así que un socket que
+		// ya exista es un resto de una ejecución anterior.
+		os.Remove(socketPath)
+		listener, err := net.Listen("unix", socketPath)
+		if err != nil {
+			return nil, fmt.Errorf("listen on %s: %w", socketPath, err)
+		}
+		os.Chmod(socketPath, 0600)
+		return listener, nil
+	}
+
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, fmt.Errorf("automation address %q: %w", address, err)
+	}
+	if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+		return nil, fmt.Errorf("automation address %q is not a loopback address", address)
+	}
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("listen on %s: %w", address, err)
+	}
+	return listener, nil
+}
+
+// loadToken lee el token de TokenPath, generándolo la primera vez.
+func loadToken() (string, error) {
+	raw, err := os.ReadFile(TokenPath)
+	if err == nil && len(strings.TrimSpace(string(raw))) > 0 {
+		return strings.TrimSpace(string(raw)), nil
+	}
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(b)
+	if err := os.WriteFile(TokenPath, []byte(token+"\n"), 0600); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// authorize rechaza con 401 las peticiones sin el token.
+func authorize(token string, next http.Handler) http.Handler {
+	want := []byte("Bearer " + token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got := []byte(r.Header.Get("Authorization"))
+		if subtle.ConstantTimeCompare(got, want) != 1 {
+			slog.Warn("automation request rejected", "method", r.Method, "path", r.URL.Path)
+			writeJSON(w, http.StatusUnauthorized, errorBody{Error: "missing or invalid token"})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// errorBody es la respuesta de las peticiones que fallan.
+type errorBody struct {
+	Error string `json:"error"`
+}
+
+// bind prepara la llamada a una ruta: Handle recibe un contexto sin ventana de su
+// herramienta y HandleTool, la herramienta abierta en la ventana.
+func bind(host Host, e tools.ToolEndpoint) func(r *http.Request) (any, error) {
+	if e.HandleTool != nil {
+		return func(r *http.Request) (any, error) {
+			tool, ctx, err := host.Tool(e.Tool)
+			if err != nil {
+				return nil, err
+			}
+			return e.HandleTool(tool, ctx, r)
+		}
+	}
+	return func(r *http.Request) (any, error) {
+		var ctx *tools.ToolContext
+		if e.Tool != "" {
+			ctx = host.Context(e.Tool)
+		}
+		return e.Handle(ctx, r)
+	}
+}
+
+// handler adapta una ruta: limita el tamaño del cuerpo, envía su resultado como
+// JSON o traduce su error al código HTTP correspondiente.
+func handler(handle func(r *http.Request) (any, error)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.Body = http.MaxBytesReader(w, r.Body, maxBodySize)
+
+		var result any
+		var err error
+		if perr := tools.Safely("automation", func() { result, err = handle(r) }); perr != nil {
+			err = perr
+		}
+
+		status := http.StatusOK
+		switch {
+		case err == nil:
+		case errors.Is(err, tools.ErrUsage):
+			status = http.StatusBadRequest
+		case errors.Is(err, tools.ErrNotFound):
+			status = http.StatusNotFound
+		default:
+			status = http.StatusInternalServerError
+		}
+		slog.Info("automation request", "method", r.Method, "path", r.URL.Path, "status", status)
+
+		if err != nil {
+			if status == http.StatusInternalServerError {
+				slog.Error("automation request failed", "method", r.Method, "path", r.URL.Path, "err", err)
+			}
+			writeJSON(w, status, errorBody{Error: err.Error()})
+			return
+		}
+		writeJSON(w, status, result)
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// route describe una ruta en la respuesta de GET /v1.
+type route struct {
+	Method  string `json:"method"`
+	Path    string `json:"path"`
+	Summary string `json:"summary"`
+}
+
+func describe(endpoints []tools.ToolEndpoint) []route {
+	routes := make([]route, len(endpoints))
+	for i, e := range endpoints {
+		routes[i] = route{Method: e.Method, Path: e.Path, Summary: e.Summary}
+	}
+	return routes
+}
+
+// builtinEndpoints son las rutas de la propia aplicación, que no pertenecen a
+// ninguna herramienta.
+func builtinEndpoints(host Host) []tools.ToolEndpoint {
+	return []tools.ToolEndpoint{{Endpoint: tools.Endpoint{
+		Method:  http.MethodPost,
+		Path:    "/v1/open",
+		Summary: `Open files in the tool that accepts them, e.g. queue PDFs in the merger: {"files": ["/abs/a.pdf"]}`,
+		Handle: func(_ *tools.ToolContext, r *http.Request) (any, error) {
+			return handleOpen(r, host.Open)
+		},
+	}}}
+}
+
+// openRequest es el cuerpo de POST /v1/open.
+type openRequest struct {
+	Files []string `json:"files"`
+}
+
+// openResult es la respuesta de POST /v1/open.
+type openResult struct {
+	Files int `json:"files"`
+}
+
+func handleOpen(r *http.Request, open func(paths []string)) (any, error) {
+	var req openRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, fmt.Errorf("%w: %v", tools.ErrUsage, err)
+	}
+	if len(req.Files) == 0 {
+		return nil, fmt.Errorf("%w: no files", tools.ErrUsage)
+	}
+	for _, path := range req.Files {
+		if !filepath.IsAbs(path) {
+			return nil, fmt.Errorf("%w: %q is not an absolute path", tools.ErrUsage, path)
+		}
+		if info, err := os.Stat(path); err != nil || info.IsDir() {
+			return nil, fmt.Errorf("file %q %w", path, tools.ErrNotFound)
+		}
+	}
+	open(req.Files)
+	return openResult{Files: len(req.Files)}, nil
+}
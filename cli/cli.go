@@ -0,0 +1,166 @@
+// Package cli implementa el modo de línea de comandos (sin interfaz gráfica).
+//
+// Los comandos los aportan las propias herramientas a través de
+// tools.ToolDescriptor.Commands, así que la CLI llama exactamente a los mismos
+// backends que la interfaz gráfica:
+//
+//	multitool pdf merge -o out.pdf a.pdf:1-3 b.pdf
+//	multitool --json net list
+//	multitool workflow run "Merge and count" a.pdf b.pdf
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+
+	"github.com/Lec7ral/MultiTool/i18n"
+	"github.com/Lec7ral/MultiTool/tools"
+	"github.com/Lec7ral/MultiTool/workflow"
+)
+
+// Códigos de salida.
+const (
+	ExitOK    = 0
+	ExitError = 1 // El comando falló.
+	ExitUsage = 2 // Comando desconocido o argumentos incorrectos.
+)
+
+// IsCommand indica si args (sin el nombre del programa) piden el modo de línea de
+// comandos en lugar de la interfaz gráfica.
+func IsCommand(args []string) bool {
+	for _, arg := range args {
+		switch {
+		case arg == "--json" || arg == "-json":
+			continue
+		case arg == "help" || arg == "-h" || arg == "-help" || arg == "--help":
+			return true
+		default:
+			return len(findGroup(arg)) > 0
+		}
+	}
+	return false
+}
+
+// Run ejecuta el comando descrito por args y devuelve el código de salida.
+func Run(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("multitool", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	jsonOutput := fs.Bool("json", false, "print results as JSON")
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintln(stderr, "multitool:", err)
+		printUsage(stderr)
+		return ExitUsage
+	}
+	args = fs.Args()
+
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "-help" || args[0] == "--help" {
+		printUsage(stdout)
+		return ExitOK
+	}
+
+	group := findGroup(args[0])
+	if len(group) == 0 {
+		fmt.Fprintf(stderr, "multitool: unknown command %q\n", args[0])
+		printUsage(stderr)
+		return ExitUsage
+	}
+	if len(args) < 2 {
+		printGroupUsage(stderr, group)
+		return ExitUsage
+	}
+
+	var cmd *tools.Command
+	for i := range group {
+		if group[i].Name == args[1] {
+			cmd = &group[i]
+		}
+	}
+	if cmd == nil {
+		fmt.Fprintf(stderr, "multitool: unknown command %q\n", args[0]+" "+args[1])
+		printGroupUsage(stderr, group)
+		return ExitUsage
+	}
+
+	// Ctrl+C cancela el contexto del comando.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	result, err := cmd.Run(ctx, args[2:])
+	if err != nil {
+		if *jsonOutput {
+			writeJSON(stdout, map[string]string{"error": err.Error()})
+		} else {
+			fmt.Fprintln(stderr, "multitool:", err)
+		}
+		if errors.Is(err, tools.ErrUsage) {
+			if !*jsonOutput {
+				fmt.Fprintf(stderr, "usage: multitool %s %s %s\n", cmd.Group, cmd.Name, cmd.Usage)
+			}
+			return ExitUsage
+		}
+		return ExitError
+	}
+
+	switch {
+	case *jsonOutput:
+		writeJSON(stdout, result)
+	case result == nil:
+	default:
+		if s, ok := result.(fmt.Stringer); ok {
+			fmt.Fprintln(stdout, s.String())
+		} else {
+			fmt.Fprintln(stdout, result)
+		}
+	}
+	return ExitOK
+}
+
+// allCommands devuelve los comandos de las herramientas y los de los flujos de trabajo.
+func allCommands() []tools.Command {
+	return append(tools.RegisteredCommands(), workflow.Commands...)
+}
+
+// findGroup devuelve los comandos registrados bajo el grupo name.
+func findGroup(name string) []tools.Command {
+	var result []tools.Command
+	for _, c := range allCommands() {
+		if c.Group == name {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+func writeJSON(w io.Writer, v any) {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "usage: multitool [--json] <command> <subcommand> [arguments]")
+	fmt.Fprintln(w, "       multitool [file...]   (start the graphical interface)")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "commands:")
+
+	commands := allCommands()
+	sort.SliceStable(commands, func(i, j int) bool { return commands[i].Group < commands[j].Group })
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %-28s %s\n", strings.TrimSpace(c.Group+" "+c.Name+" "+c.Usage), i18n.T(c.Summary))
+	}
+}
+
+func printGroupUsage(w io.Writer, group []tools.Command) {
+	fmt.Fprintln(w, "usage:")
+	for _, c := range group {
+		fmt.Fprintf(w, "  multitool %s %s %s\n", c.Group, c.Name, c.Usage)
+	}
+}
@@ -0,0 +1,7 @@
+//go:build !windows
+
+package cli
+
+// AttachConsole no hace nada fuera de Windows: allí los programas gráficos
+// heredan la salida estándar de quien los lanza.
+func AttachConsole() {}
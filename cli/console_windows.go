@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"os"
+	"syscall"
+)
+
+var (
+	kernel32          = syscall.NewLazyDLL("kernel32.dll")
+	procAttachConsole = kernel32.NewProc("AttachConsole")
+)
+
+// attachParentProcess es ATTACH_PARENT_PROCESS: la consola del proceso padre.
+const attachParentProcess = ^uintptr(0)
+
+// AttachConsole conecta la salida estándar y la de errores a la consola desde la
+// que se lanzó MultiTool. El binario de release es una aplicación gráfica de
+// Windows, que no tiene consola propia: sin esto, lo que escribe un comando en
+// cmd o en un archivo por lotes se perdería. Si la salida ya está redirigida (a un
+// archivo o a una tubería) se respeta; si no hay consola padre, no hace nada.
+func AttachConsole() {
+	if r, _, _ := procAttachConsole.Call(attachParentProcess); r == 0 {
+		return // Ya tenemos consola, o nos lanzó el Explorador.
+	}
+	if !usable(os.Stdout) || !usable(os.Stderr) {
+		conout, err := os.OpenFile("CONOUT$", os.O_WRONLY, 0)
+		if err != nil {
+			return
+		}
+		if !usable(os.Stdout) {
+			os.Stdout = conout
+		}
+		if !usable(os.Stderr) {
+			os.Stderr = conout
+		}
+	}
+}
+
+// usable indica si f es un descriptor válido (un archivo, tubería o consola).
+func usable(f *os.File) bool {
+	if f == nil {
+		return false
+	}
+	_, err := f.Stat()
+	return err == nil
+}
@@ -0,0 +1,34 @@
+// Package config localiza el directorio de configuración de MultiTool
+// (UserConfigDir()/MultiTool), donde se guardan los perfiles y el resto de
+// archivos de la aplicación.
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+var (
+	dirOnce sync.Once
+	dir     string
+)
+
+// Dir devuelve el directorio de configuración de la aplicación, creándolo si no existe.
+func Dir() string {
+	dirOnce.Do(func() {
+		configDir, err := os.UserConfigDir()
+		if err != nil {
+			// Fallback if config dir is not found
+			configDir = "."
+		}
+		dir = filepath.Join(configDir, "MultiTool")
+		os.MkdirAll(dir, os.ModePerm)
+	})
+	return dir
+}
+
+// Path devuelve la ruta de name dentro del directorio de configuración.
+func Path(name string) string {
+	return filepath.Join(Dir(), name)
+}
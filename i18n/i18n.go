@@ -0,0 +1,138 @@
+// Package i18n traduce los textos de la interfaz.
+//
+// Los textos se escriben en inglés en el código y se traducen con T:
+//
+//	widget.NewButton(i18n.T("Merge PDFs"), ...)
+//	status.SetStatus(i18n.T("PDFs merged into %s", outFile))
+//
+// Las traducciones están en catálogos JSON, uno por idioma (es.json, ...), que
+// asocian cada texto en inglés con su traducción. La interfaz y cada herramienta
+// registran los suyos desde su init() con AddCatalogFS. Un texto sin traducción
+// se muestra en inglés.
+package i18n
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"fyne.io/fyne/v2/lang"
+)
+
+// Source es el idioma en el que están escritos los textos del código.
+const Source = "en"
+
+var (
+	mu       sync.RWMutex
+	catalogs = map[string]map[string]string{Source: {}}
+	current  string // Idioma elegido; vacío hasta la primera llamada a SetLanguage.
+)
+
+// AddCatalog añade las traducciones de messages al idioma lang ("es", "pt"...).
+// Si un texto ya estaba traducido, gana la última traducción.
+func AddCatalog(lang string, messages map[string]string) {
+	lang = normalize(lang)
+	mu.Lock()
+	defer mu.Unlock()
+	catalog := catalogs[lang]
+	if catalog == nil {
+		catalog = make(map[string]string, len(messages))
+		catalogs[lang] = catalog
+	}
+	for source, translated := range messages {
+		catalog[source] = translated
+	}
+}
+
+// AddCatalogFS añade todos los catálogos de la carpeta dir de fsys. Cada archivo
+// se llama <idioma>.json.
+func AddCatalogFS(fsys fs.FS, dir string) error {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || path.Ext(name) != ".json" {
+			continue
+		}
+		raw, err := fs.ReadFile(fsys, path.Join(dir, name))
+		if err != nil {
+			return err
+		}
+		var messages map[string]string
+		if err := json.Unmarshal(raw, &messages); err != nil {
+			return fmt.Errorf("parse %s: %w", name, err)
+		}
+		AddCatalog(strings.TrimSuffix(name, ".json"), messages)
+	}
+	return nil
+}
+
+// Languages devuelve los idiomas con catálogo, ordenados.
+func Languages() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	result := make([]string, 0, len(catalogs))
+	for lang := range catalogs {
+		result = append(result, lang)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// SystemLanguage devuelve el idioma del sistema ("es", "en"...).
+func SystemLanguage() string {
+	return normalize(lang.SystemLocale().LanguageString())
+}
+
+// SetLanguage elige el idioma de la interfaz. Con "" se usa el del sistema. Si no
+// hay catálogo para el idioma, los textos se muestran en inglés.
+func SetLanguage(lang string) {
+	if lang == "" {
+		lang = SystemLanguage()
+	}
+	mu.Lock()
+	current = normalize(lang)
+	mu.Unlock()
+}
+
+// Language devuelve el idioma en uso.
+func Language() string {
+	mu.RLock()
+	lang := current
+	mu.RUnlock()
+	if lang == "" {
+		SetLanguage("")
+		return Language()
+	}
+	return lang
+}
+
+// T traduce text al idioma en uso. Si se pasan args, text es un formato de
+// fmt.Sprintf y se aplica a la traducción.
+func T(text string, args ...any) string {
+	lang := Language()
+	mu.RLock()
+	if translated, ok := catalogs[lang][text]; ok && translated != "" {
+		text = translated
+	}
+	mu.RUnlock()
+	if len(args) > 0 {
+		return fmt.Sprintf(text, args...)
+	}
+	return text
+}
+
+// normalize reduce una etiqueta de idioma ("es-ES", "es_AR") a su idioma base ("es").
+func normalize(lang string) string {
+	lang = strings.ToLower(lang)
+	if i := strings.IndexAny(lang, "-_"); i > 0 {
+		lang = lang[:i]
+	}
+	return lang
+}
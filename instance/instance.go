@@ -0,0 +1,164 @@
+// Package instance garantiza que solo haya una instancia de MultiTool en marcha.
+//
+// La primera instancia bloquea el archivo instance.lock y escucha en un socket
+// local, ambos en el directorio de configuración. Las siguientes encuentran el
+// archivo bloqueado, se conectan al socket, le envían sus argumentos de línea de
+// comandos y terminan, de modo que "multitool a.pdf b.pdf" abre los archivos en
+// la ventana ya existente.
+package instance
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"time"
+
+	"github.com/Lec7ral/MultiTool/config"
+)
+
+// ErrAlreadyRunning indica que ya había otra instancia y que se le han enviado los argumentos.
+var ErrAlreadyRunning = errors.New("another instance is already running")
+
+// ErrNoResponse indica que hay una instancia principal pero no ha confirmado los
+// argumentos. El llamador debe terminar igualmente: convertirse en
+// una segunda instancia principal le quitaría el socket a la primera.
+var ErrNoResponse = errors.New("another instance is running but did not respond")
+
+const dialTimeout = 2 * time.Second
+
+// Request es lo que una segunda instancia envía a la principal.
+type Request struct {
+	Args []string `json:"args"`
+	Dir  string   `json:"dir"` // Directorio de trabajo, para resolver rutas relativas.
+}
+
+// Server es el lado de la instancia principal.
+type Server struct {
+	listener net.Listener
+	lock     *os.File // instance.lock, bloqueado mientras la instancia vive.
+}
+
+// socketPath devuelve la ruta del socket de la instancia principal.
+func socketPath() string {
+	return config.Path("instance.sock")
+}
+
+// lockPath devuelve la ruta del archivo que bloquea la instancia principal.
+func lockPath() string {
+	return config.Path("instance.lock")
+}
+
+// Acquire intenta convertirse en la instancia principal. Si ya hay una, le envía
+// args y devuelve ErrAlreadyRunning; el llamador debe terminar en ese caso.
+//
+// Ser la instancia principal es tener bloqueado instance.lock. El sistema libera
+// el bloqueo cuando el proceso termina, aunque sea de mala manera, así que dos
+// instancias lanzadas a la vez nunca pueden creerse las dos principales.
+func Acquire(args []string) (*Server, error) {
+	path := socketPath()
+
+	lock, err := os.OpenFile(lockPath(), os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("open lock file: %w", err)
+	}
+	locked, err := tryLock(lock)
+	if err != nil {
+		lock.Close()
+		return nil, fmt.Errorf("lock %s: %w", lock.Name(), err)
+	}
+	if !locked {
+		lock.Close()
+		return nil, forwardToPrimary(path, args)
+	}
+
+	// Somos la instancia principal: un socket que ya exista es un resto de una
+	// que terminó mal.
+	os.Remove(path)
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		lock.Close()
+		return nil, fmt.Errorf("listen on %s: %w", path, err)
+	}
+	return &Server{listener: listener, lock: lock}, nil
+}
+
+// forwardToPrimary envía args a la instancia principal. Si acaba de arrancar y
+// aún no escucha, lo reintenta durante dialTimeout. Devuelve ErrAlreadyRunning
+// si se entregaron y un error que envuelve ErrNoResponse si no.
+func forwardToPrimary(path string, args []string) error {
+	deadline := time.Now().Add(dialTimeout)
+	for {
+		err := forward(path, args)
+		if err == nil {
+			return ErrAlreadyRunning
+		}
+		if !notListening(err) || time.Now().After(deadline) {
+			return fmt.Errorf("%w: %v", ErrNoResponse, err)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// notListening indica si err, al conectar con el socket, significa que nadie
+// escucha en él (todavía).
+func notListening(err error) bool {
+	return errors.Is(err, fs.ErrNotExist) || refused(err)
+}
+
+// forward envía args a la instancia principal y espera su confirmación.
+func forward(path string, args []string) error {
+	conn, err := net.DialTimeout("unix", path, dialTimeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(dialTimeout))
+
+	dir, _ := os.Getwd()
+	if err := json.NewEncoder(conn).Encode(Request{Args: args, Dir: dir}); err != nil {
+		return err
+	}
+	reply, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		return err
+	}
+	if reply != "ok\n" {
+		return fmt.Errorf("unexpected reply %q", reply)
+	}
+	return nil
+}
+
+// Serve atiende las peticiones de otras instancias hasta que se llame a Close.
+// handler se llama desde la goroutine del servidor. Conviene llamarlo nada más
+// obtener el Server: mientras no se atiende, las otras instancias no reciben
+// respuesta y terminan con ErrNoResponse.
+func (s *Server) Serve(handler func(Request)) {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return // Close() cierra el listener.
+		}
+		go func() {
+			defer conn.Close()
+			conn.SetDeadline(time.Now().Add(dialTimeout))
+
+			var req Request
+			if err := json.NewDecoder(conn).Decode(&req); err != nil {
+				return
+			}
+			conn.Write([]byte("ok\n"))
+			handler(req)
+		}()
+	}
+}
+
+// Close deja de escuchar, elimina el socket y libera el bloqueo.
+func (s *Server) Close() error {
+	err := s.listener.Close()
+	s.lock.Close()
+	return err
+}
@@ -0,0 +1,35 @@
+package instance
+
+import (
+	"errors"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	kernel32       = syscall.NewLazyDLL("kernel32.dll")
+	procLockFileEx = kernel32.NewProc("LockFileEx")
+)
+
+const (
+	lockfileFailImmediately = 0x00000001
+	lockfileExclusiveLock   = 0x00000002
+
+	// errorLockViolation es ERROR_LOCK_VIOLATION: otro proceso tiene el bloqueo.
+	errorLockViolation syscall.Errno = 33
+)
+
+// tryLock bloquea f en exclusiva sin esperar. Devuelve false si otro proceso ya
+// lo tiene bloqueado.
+func tryLock(f *os.File) (bool, error) {
+	var overlapped syscall.Overlapped
+	r, _, err := procLockFileEx.Call(f.Fd(), lockfileExclusiveLock|lockfileFailImmediately, 0, 1, 0, uintptr(unsafe.Pointer(&overlapped)))
+	if r != 0 {
+		return true, nil
+	}
+	if errors.Is(err, errorLockViolation) {
+		return false, nil
+	}
+	return false, err
+}
@@ -0,0 +1,13 @@
+//go:build !windows
+
+package instance
+
+import (
+	"errors"
+	"syscall"
+)
+
+// refused indica si la conexión se rechazó porque no hay nadie escuchando en el socket.
+func refused(err error) bool {
+	return errors.Is(err, syscall.ECONNREFUSED)
+}
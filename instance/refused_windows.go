@@ -0,0 +1,14 @@
+package instance
+
+import (
+	"errors"
+	"syscall"
+)
+
+// wsaeConnRefused es WSAECONNREFUSED: no hay nadie escuchando en el socket.
+const wsaeConnRefused syscall.Errno = 10061
+
+// refused indica si la conexión se rechazó porque no hay nadie escuchando en el socket.
+func refused(err error) bool {
+	return errors.Is(err, wsaeConnRefused)
+}
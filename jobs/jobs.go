@@ -0,0 +1,298 @@
+// Package jobs ejecuta tareas largas en segundo plano (fusionar PDFs, aplicar un
+// perfil de red...) para no bloquear la interfaz. Cada tarea recibe un
+// context.Context que se cancela desde la UI, informa de su progreso y puede
+// escribir líneas de log que se muestran en el panel de tareas.
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// State es el estado de una tarea.
+type State int
+
+const (
+	Running State = iota
+	Succeeded
+	Failed
+	Canceled
+)
+
+func (s State) String() string {
+	switch s {
+	case Running:
+		return "Running"
+	case Succeeded:
+		return "Done"
+	case Failed:
+		return "Failed"
+	case Canceled:
+		return "Canceled"
+	}
+	return "Unknown"
+}
+
+// maxFinished es el número de tareas terminadas que se conservan en el historial.
+const maxFinished = 50
+
+// Reporter es lo que ve una tarea en ejecución para informar de su avance. Los
+// backends lo reciben como parámetro para no depender de la UI.
+type Reporter interface {
+	// SetProgress fija el progreso entre 0 y 1. Un valor negativo significa
+	// progreso indeterminado.
+	SetProgress(fraction float64)
+	Logf(format string, args ...any)
+}
+
+// Discard es un Reporter que no hace nada, para llamar a los backends fuera de
+// una tarea (por ejemplo desde la línea de comandos).
+var Discard Reporter = discard{}
+
+type discard struct{}
+
+func (discard) SetProgress(float64) {}
+func (discard) Logf(string, ...any) {}
+
+// Func es el trabajo de una tarea. Debe terminar en cuanto ctx se cancele.
+type Func func(ctx context.Context, r Reporter) error
+
+// Info es una instantánea inmutable de una tarea, para mostrarla en la UI.
+type Info struct {
+	ID       int
+	Tool     string
+	Title    string
+	State    State
+	Progress float64
+	Logs     []string
+	Err      error
+	Started  time.Time
+	Finished time.Time
+}
+
+// Job es una tarea enviada al Manager.
+type Job struct {
+	manager *Manager
+	cancel  context.CancelFunc
+	done    chan struct{}
+
+	mu   sync.Mutex
+	info Info
+}
+
+// SetProgress implementa Reporter.
+func (j *Job) SetProgress(fraction float64) {
+	if fraction > 1 {
+		fraction = 1
+	}
+	j.mu.Lock()
+	j.info.Progress = fraction
+	j.mu.Unlock()
+	j.manager.changed()
+}
+
+// Logf implementa Reporter.
+func (j *Job) Logf(format string, args ...any) {
+	line := time.Now().Format("15:04:05") + "  " + fmt.Sprintf(format, args...)
+	j.mu.Lock()
+	j.info.Logs = append(j.info.Logs, line)
+	j.mu.Unlock()
+	j.manager.changed()
+}
+
+// Cancel pide a la tarea que se detenga.
+func (j *Job) Cancel() {
+	j.cancel()
+}
+
+// Wait espera a que la tarea termine y devuelve su error.
+func (j *Job) Wait() error {
+	<-j.done
+	return j.Info().Err
+}
+
+// Info devuelve una instantánea del estado de la tarea.
+func (j *Job) Info() Info {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	info := j.info
+	info.Logs = append([]string(nil), j.info.Logs...)
+	return info
+}
+
+// Manager ejecuta las tareas y mantiene la lista de tareas en curso y terminadas.
+// Es seguro para uso concurrente.
+type Manager struct {
+	mu        sync.Mutex
+	jobs      []*Job
+	nextID    int
+	listeners map[int]func()
+	nextLis   int
+}
+
+// NewManager crea un Manager vacío.
+func NewManager() *Manager {
+	return &Manager{listeners: make(map[int]func())}
+}
+
+// Submit lanza fn en una goroutine y devuelve la tarea. tool es el nombre de la
+// herramienta que la envía y title una descripción corta para el panel de tareas.
+func (m *Manager) Submit(tool, title string, fn Func) *Job {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	m.mu.Lock()
+	m.nextID++
+	j := &Job{
+		manager: m,
+		cancel:  cancel,
+		done:    make(chan struct{}),
+		info:    Info{ID: m.nextID, Tool: tool, Title: title, State: Running, Progress: -1, Started: time.Now()},
+	}
+	m.jobs = append(m.jobs, j)
+	m.pruneLocked()
+	m.mu.Unlock()
+	m.changed()
+
+	go func() {
+		defer close(j.done)
+		defer cancel()
+		j.finish(ctx, j.run(ctx, fn))
+	}()
+	return j
+}
+
+// run ejecuta fn. Un pánico hace fallar la tarea (y queda en el log con su
+// traza) en lugar de terminar la aplicación.
+func (j *Job) run(ctx context.Context, fn Func) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("job panicked", "tool", j.info.Tool, "job", j.info.Title, "panic", fmt.Sprint(r), "stack", string(debug.Stack()))
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return fn(ctx, j)
+}
+
+// finish registra el resultado de la tarea.
+func (j *Job) finish(ctx context.Context, err error) {
+	j.mu.Lock()
+	j.info.Finished = time.Now()
+	j.info.Err = err
+	switch {
+	case err == nil:
+		j.info.State = Succeeded
+		j.info.Progress = 1
+	case errors.Is(err, context.Canceled) || ctx.Err() != nil:
+		j.info.State = Canceled
+	default:
+		j.info.State = Failed
+	}
+	j.mu.Unlock()
+	j.manager.changed()
+}
+
+// Jobs devuelve una instantánea de todas las tareas, de la más reciente a la más antigua.
+func (m *Manager) Jobs() []Info {
+	m.mu.Lock()
+	jobs := append([]*Job(nil), m.jobs...)
+	m.mu.Unlock()
+
+	result := make([]Info, 0, len(jobs))
+	for i := len(jobs) - 1; i >= 0; i-- {
+		result = append(result, jobs[i].Info())
+	}
+	return result
+}
+
+// Get devuelve la tarea con el identificador id, o nil.
+func (m *Manager) Get(id int) *Job {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, j := range m.jobs {
+		if j.info.ID == id {
+			return j
+		}
+	}
+	return nil
+}
+
+// Running devuelve cuántas tareas siguen en ejecución.
+func (m *Manager) Running() int {
+	n := 0
+	for _, info := range m.Jobs() {
+		if info.State == Running {
+			n++
+		}
+	}
+	return n
+}
+
+// ClearFinished elimina del historial las tareas terminadas.
+func (m *Manager) ClearFinished() {
+	m.mu.Lock()
+	kept := m.jobs[:0]
+	for _, j := range m.jobs {
+		if j.Info().State == Running {
+			kept = append(kept, j)
+		}
+	}
+	m.jobs = kept
+	m.mu.Unlock()
+	m.changed()
+}
+
+// CancelAll cancela todas las tareas en ejecución.
+func (m *Manager) CancelAll() {
+	m.mu.Lock()
+	jobs := append([]*Job(nil), m.jobs...)
+	m.mu.Unlock()
+	for _, j := range jobs {
+		j.Cancel()
+	}
+}
+
+// OnChange registra fn para que se llame (desde cualquier goroutine) cada vez que
+// cambie una tarea. Devuelve una función que anula el registro.
+func (m *Manager) OnChange(fn func()) (remove func()) {
+	m.mu.Lock()
+	m.nextLis++
+	id := m.nextLis
+	m.listeners[id] = fn
+	m.mu.Unlock()
+	return func() {
+		m.mu.Lock()
+		delete(m.listeners, id)
+		m.mu.Unlock()
+	}
+}
+
+func (m *Manager) changed() {
+	m.mu.Lock()
+	listeners := make([]func(), 0, len(m.listeners))
+	for _, fn := range m.listeners {
+		listeners = append(listeners, fn)
+	}
+	m.mu.Unlock()
+	for _, fn := range listeners {
+		fn()
+	}
+}
+
+// pruneLocked descarta las tareas terminadas más antiguas por encima de maxFinished.
+func (m *Manager) pruneLocked() {
+	finished := 0
+	for i := len(m.jobs) - 1; i >= 0; i-- {
+		if m.jobs[i].Info().State == Running {
+			continue
+		}
+		finished++
+		if finished > maxFinished {
+			m.jobs = append(m.jobs[:i], m.jobs[i+1:]...)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Entry es un registro del búfer en memoria.
+type Entry struct {
+	Time    time.Time
+	Level   slog.Level
+	Tool    string // Valor del atributo ToolKey, si lo tiene.
+	Message string
+	Attrs   string // Resto de atributos, como "clave=valor".
+}
+
+// String da el formato con el que el visor muestra y copia el registro.
+func (e Entry) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %-5s ", e.Time.Format("15:04:05"), e.Level)
+	if e.Tool != "" {
+		fmt.Fprintf(&b, "[%s] ", e.Tool)
+	}
+	b.WriteString(e.Message)
+	if e.Attrs != "" {
+		b.WriteString(" ")
+		b.WriteString(e.Attrs)
+	}
+	return b.String()
+}
+
+// ringBuffer guarda los últimos registros.
+type ringBuffer struct {
+	mu        sync.Mutex
+	size      int
+	items     []Entry
+	listeners map[int]func()
+	nextID    int
+}
+
+func newRingBuffer(size int) *ringBuffer {
+	return &ringBuffer{size: size, listeners: make(map[int]func())}
+}
+
+func (b *ringBuffer) add(e Entry) {
+	b.mu.Lock()
+	if len(b.items) == b.size {
+		copy(b.items, b.items[1:])
+		b.items[len(b.items)-1] = e
+	} else {
+		b.items = append(b.items, e)
+	}
+	listeners := make([]func(), 0, len(b.listeners))
+	for _, fn := range b.listeners {
+		listeners = append(listeners, fn)
+	}
+	b.mu.Unlock()
+
+	for _, fn := range listeners {
+		fn()
+	}
+}
+
+func (b *ringBuffer) entries() []Entry {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]Entry(nil), b.items...)
+}
+
+func (b *ringBuffer) onChange(fn func()) func() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	id := b.nextID
+	b.nextID++
+	b.listeners[id] = fn
+	return func() {
+		b.mu.Lock()
+		delete(b.listeners, id)
+		b.mu.Unlock()
+	}
+}
+
+// bufferHandler es el slog.Handler que escribe en el búfer.
+type bufferHandler struct {
+	buffer *ringBuffer
+	level  slog.Leveler
+	tool   string
+	attrs  []string
+	group  string // Prefijo de los atributos ("grupo.").
+}
+
+func (h *bufferHandler) Enabled(_ context.Context, l slog.Level) bool {
+	return l >= h.level.Level()
+}
+
+func (h *bufferHandler) Handle(_ context.Context, r slog.Record) error {
+	e := Entry{Time: r.Time, Level: r.Level, Tool: h.tool, Message: r.Message}
+	attrs := append([]string(nil), h.attrs...)
+	r.Attrs(func(a slog.Attr) bool {
+		if a.Key == ToolKey && h.group == "" {
+			e.Tool = a.Value.String()
+		} else {
+			attrs = append(attrs, h.group+a.Key+"="+a.Value.String())
+		}
+		return true
+	})
+	e.Attrs = strings.Join(attrs, " ")
+	h.buffer.add(e)
+	return nil
+}
+
+func (h *bufferHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.attrs = append([]string(nil), h.attrs...)
+	for _, a := range attrs {
+		if a.Key == ToolKey && h.group == "" {
+			clone.tool = a.Value.String()
+		} else {
+			clone.attrs = append(clone.attrs, h.group+a.Key+"="+a.Value.String())
+		}
+	}
+	return &clone
+}
+
+func (h *bufferHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := *h
+	clone.group = h.group + name + "."
+	return &clone
+}
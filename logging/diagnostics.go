@@ -0,0 +1,57 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"strings"
+
+	"github.com/Lec7ral/MultiTool/config"
+)
+
+// diagnosticLines es el número de líneas del archivo de log que incluye Diagnostics.
+const diagnosticLines = 300
+
+// Diagnostics reúne en un texto la información útil para un informe de errores:
+// versiones de la aplicación, de Go y de las dependencias principales, el sistema
+// y las últimas líneas del archivo de log.
+func Diagnostics(appName, appVersion string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s\n", appName, appVersion)
+	fmt.Fprintf(&b, "Go %s %s/%s\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, dep := range info.Deps {
+			switch dep.Path {
+			case "fyne.io/fyne/v2", "github.com/pdfcpu/pdfcpu":
+				fmt.Fprintf(&b, "%s %s\n", dep.Path, dep.Version)
+			}
+		}
+		for _, s := range info.Settings {
+			if s.Key == "-tags" || s.Key == "vcs.revision" {
+				fmt.Fprintf(&b, "%s %s\n", s.Key, s.Value)
+			}
+		}
+	}
+	fmt.Fprintf(&b, "Config dir: %s\n", config.Dir())
+	fmt.Fprintf(&b, "Log level: %s\n", Level())
+
+	fmt.Fprintf(&b, "\n--- %s (last %d lines) ---\n", fileName, diagnosticLines)
+	raw, err := os.ReadFile(FilePath())
+	if err != nil {
+		// Sin archivo, al menos incluimos lo que hay en memoria.
+		fmt.Fprintf(&b, "(%v)\n", err)
+		for _, e := range Entries() {
+			b.WriteString(e.String())
+			b.WriteString("\n")
+		}
+		return b.String()
+	}
+	lines := strings.Split(strings.TrimRight(string(raw), "\n"), "\n")
+	if len(lines) > diagnosticLines {
+		lines = lines[len(lines)-diagnosticLines:]
+	}
+	b.WriteString(strings.Join(lines, "\n"))
+	b.WriteString("\n")
+	return b.String()
+}
@@ -0,0 +1,166 @@
+// Package logging configura el log de la aplicación.
+//
+// Todo lo que se escribe con log/slog (y con el paquete log, que usa Fyne para
+// sus errores) va a tres sitios:
+//
+//   - logs/multitool.log en el directorio de configuración, en JSON, un registro
+//     por línea; el archivo rota al llegar a 1 MB y se guardan 3 anteriores;
+//   - la salida de errores, en texto;
+//   - un búfer en memoria con los últimos registros, para el visor de logs.
+//
+// Las herramientas reciben en ToolContext.Logger un logger con el atributo
+// "tool" ya puesto, que el visor usa para filtrar.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Lec7ral/MultiTool/config"
+)
+
+const (
+	fileName    = "multitool.log"
+	maxFileSize = 1 << 20
+	maxBackups  = 3
+)
+
+// ToolKey es el atributo con el nombre de la herramienta que escribió un registro.
+const ToolKey = "tool"
+
+var (
+	level   = new(slog.LevelVar)
+	buffer  = newRingBuffer(2000)
+	logFile *rotatingFile
+)
+
+// Dir devuelve la carpeta de los archivos de log.
+func Dir() string {
+	return config.Path("logs")
+}
+
+// FilePath devuelve la ruta del archivo de log actual.
+func FilePath() string {
+	return filepath.Join(Dir(), fileName)
+}
+
+// Setup instala el logger de la aplicación como logger por defecto. Si el archivo
+// no se puede abrir, el log sigue yendo a la salida de errores y al visor, y se
+// devuelve el error. La función devuelta cierra el archivo.
+func Setup() (func(), error) {
+	opts := &slog.HandlerOptions{Level: level}
+	handlers := []slog.Handler{
+		slog.NewTextHandler(os.Stderr, opts),
+		&bufferHandler{buffer: buffer, level: level},
+	}
+
+	file, err := openRotatingFile(FilePath(), maxFileSize, maxBackups)
+	if err == nil {
+		logFile = file
+		handlers = append(handlers, slog.NewJSONHandler(file, opts))
+	}
+
+	logger := slog.New(fanout(handlers))
+	slog.SetDefault(logger)
+
+	// El paquete log lo usa sobre todo Fyne para avisar de errores.
+	log.SetFlags(0)
+	log.SetOutput(stdLogWriter{logger: logger})
+
+	closeFn := func() {
+		if logFile != nil {
+			logFile.Close()
+		}
+	}
+	if err != nil {
+		return closeFn, fmt.Errorf("open log file: %w", err)
+	}
+	return closeFn, nil
+}
+
+// SetLevel fija el nivel mínimo que se registra.
+func SetLevel(l slog.Level) {
+	level.Set(l)
+}
+
+// Level devuelve el nivel mínimo que se registra.
+func Level() slog.Level {
+	return level.Level()
+}
+
+// ParseLevel convierte "debug", "info", "warn" o "error" en un nivel. Los valores
+// desconocidos dan LevelInfo.
+func ParseLevel(s string) slog.Level {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(s)); err != nil {
+		return slog.LevelInfo
+	}
+	return l
+}
+
+// Entries devuelve los registros del búfer, del más antiguo al más reciente.
+func Entries() []Entry {
+	return buffer.entries()
+}
+
+// OnChange registra fn para que se llame (desde la goroutine que escribió el
+// registro) con cada registro nuevo. La función devuelta anula la suscripción.
+func OnChange(fn func()) (remove func()) {
+	return buffer.onChange(fn)
+}
+
+// stdLogWriter pasa las líneas del paquete log al logger como avisos.
+type stdLogWriter struct {
+	logger *slog.Logger
+}
+
+func (w stdLogWriter) Write(p []byte) (int, error) {
+	w.logger.Warn(strings.TrimSpace(string(p)))
+	return len(p), nil
+}
+
+// fanout es un slog.Handler que reparte cada registro entre varios handlers.
+type fanout []slog.Handler
+
+func (f fanout) Enabled(ctx context.Context, l slog.Level) bool {
+	for _, h := range f {
+		if h.Enabled(ctx, l) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f fanout) Handle(ctx context.Context, r slog.Record) error {
+	var firstErr error
+	for _, h := range f {
+		if !h.Enabled(ctx, r.Level) {
+			continue
+		}
+		if err := h.Handle(ctx, r.Clone()); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (f fanout) WithAttrs(attrs []slog.Attr) slog.Handler {
+	result := make(fanout, len(f))
+	for i, h := range f {
+		result[i] = h.WithAttrs(attrs)
+	}
+	return result
+}
+
+func (f fanout) WithGroup(name string) slog.Handler {
+	result := make(fanout, len(f))
+	for i, h := range f {
+		result[i] = h.WithGroup(name)
+	}
+	return result
+}
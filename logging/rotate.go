@@ -0,0 +1,119 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// rotatingFile es un io.Writer sobre un archivo que, al superar maxSize bytes,
+// se renombra a name.1 (name.1 pasa a name.2, etc.) y se empieza uno nuevo. Se
+// conservan como mucho backups archivos antiguos.
+type rotatingFile struct {
+	path    string
+	maxSize int64
+	backups int
+
+	mu    sync.Mutex
+	file  *os.File
+	size  int64
+	limit int64 // Tamaño a partir del cual se rota; crece si una rotación falla.
+}
+
+func openRotatingFile(path string, maxSize int64, backups int) (*rotatingFile, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	r := &rotatingFile{path: path, maxSize: maxSize, backups: backups, limit: maxSize}
+	if err := r.open(path); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// open abre (o crea) path en modo append como archivo actual.
+func (r *rotatingFile) open(path string) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	r.file, r.size = f, info.Size()
+	return nil
+}
+
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.size > 0 && r.size+int64(len(p)) > r.limit {
+		if err := r.rotate(); err != nil {
+			// Si rotate ha podido seguir con el archivo grande, no se vuelve a
+			// intentar hasta que crezca otros maxSize bytes; si no queda
+			// ninguno abierto, se intenta en la próxima escritura.
+			fmt.Fprintln(os.Stderr, "log rotation failed:", err)
+			if r.file != nil {
+				r.limit = r.size + r.maxSize
+			}
+		}
+	}
+	if r.file == nil {
+		return 0, os.ErrClosed
+	}
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// rotate desplaza los archivos antiguos y abre uno nuevo. El archivo actual se
+// cierra antes de renombrarlo porque Windows no renombra archivos abiertos. Si
+// el nuevo no se puede abrir, se vuelve a abrir el anterior (con su nombre nuevo
+// o con el de siempre si no se pudo renombrar) para no dejar de registrar.
+func (r *rotatingFile) rotate() error {
+	if r.file != nil {
+		if err := r.file.Close(); err != nil {
+			return err
+		}
+		r.file = nil
+	}
+	for i := r.backups - 1; i >= 1; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", r.path, i), fmt.Sprintf("%s.%d", r.path, i+1))
+	}
+	previous := r.path
+	if r.backups > 0 {
+		if os.Rename(r.path, r.path+".1") == nil {
+			previous = r.path + ".1"
+		}
+	} else if os.Remove(r.path) == nil {
+		previous = ""
+	}
+
+	err := r.open(r.path)
+	if err == nil {
+		r.limit = r.maxSize
+		return nil
+	}
+	if previous != "" && previous != r.path {
+		if reopenErr := r.open(previous); reopenErr != nil {
+			return fmt.Errorf("%w; reopen %s: %v", err, previous, reopenErr)
+		}
+	}
+	return err
+}
+
+// Close cierra el archivo actual.
+func (r *rotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file, r.size = nil, 0 // Sin tamaño, Write no intenta rotar un archivo cerrado.
+	return err
+}
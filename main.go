@@ -1,73 +1,258 @@
 package main
 
 import (
-	"runtime"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/app"
+	"github.com/Lec7ral/MultiTool/assets"
+	"github.com/Lec7ral/MultiTool/automation"
+	"github.com/Lec7ral/MultiTool/cli"
+	"github.com/Lec7ral/MultiTool/i18n"
+	"github.com/Lec7ral/MultiTool/instance"
+	"github.com/Lec7ral/MultiTool/logging"
+	"github.com/Lec7ral/MultiTool/settings"
+	"github.com/Lec7ral/MultiTool/tools"
+	_ "github.com/Lec7ral/MultiTool/tools/builtin" // Registra las herramientas incluidas.
 	"github.com/Lec7ral/MultiTool/ui"
 )
 
 // myApp y myWindow son globales para ser accesibles desde múltiples funciones.
 var (
-	myApp    fyne.App
-	myWindow fyne.Window
+	myApp      fyne.App
+	myWindow   fyne.Window
+	myLayout   *ui.AppLayout
+	myServices *ui.AppServices
 )
 
 func main() {
-	// 1. Inicializar la aplicación.
+	// 0. Si se pide un subcomando (p.ej. "multitool pdf merge ..."), trabajamos en
+	//    modo línea de comandos, sin interfaz gráfica.
+	//    En Windows, la salida se conecta a la consola desde la que se nos lanzó.
+	if args := os.Args[1:]; cli.IsCommand(args) {
+		cli.AttachConsole()
+		os.Exit(cli.Run(args, os.Stdout, os.Stderr))
+	}
+
+	// 1. Asegurar una única instancia. Si ya hay otra en marcha, le enviamos nuestros
+	//    argumentos (p.ej. archivos que abrir) y terminamos.
+	//    Si hay otra pero no responde, también terminamos: no puede haber dos
+	//    instancias principales.
+	server, err := instance.Acquire(os.Args[1:])
+	if errors.Is(err, instance.ErrAlreadyRunning) {
+		return
+	}
+	if errors.Is(err, instance.ErrNoResponse) {
+		slog.Error("not starting a second instance", "err", err)
+		os.Exit(1)
+	}
+	// Atendemos a las demás instancias desde ya, para que no esperen a que termine
+	// el arranque; sus peticiones se guardan hasta que la ventana esté lista.
+	if server != nil {
+		go server.Serve(handleForwardedArgs)
+	}
+
+	// El log de la aplicación va a un archivo rotativo en el directorio de
+	// configuración y al visor de logs.
+	closeLog, logErr := logging.Setup()
+	defer closeLog()
+	if logErr != nil {
+		slog.Error("log file unavailable", "err", logErr)
+	}
+
+	if err != nil {
+		slog.Warn("single-instance lock unavailable", "err", err)
+	} else {
+		defer server.Close()
+	}
+
+	// 2. Inicializar la aplicación.
 	myApp = app.NewWithID("com.lec7ral.multitool")
+	myApp.SetIcon(assets.AppIcon)
 
-	// 2. Aplicar el tema.
-	ui.ApplyTheme(myApp)
+	// 3. Cargar los servicios compartidos (ajustes, tareas) y aplicar el idioma y
+	//    el tema.
+	myServices = ui.NewAppServices()
+	ui.ApplyLanguage(myServices.Settings)
+	ui.ApplyLogLevel(myServices.Settings)
+	ui.ApplyTheme(myApp, myServices.Settings)
 
-	// 3. Instalar la bandeja del sistema desde el principio. Esto es crucial para que
+	// 4. Instalar la bandeja del sistema desde el principio. Esto es crucial para que
 	//    la aplicación no se cierre cuando la última ventana se cierre.
-	ui.InstallSystray(myApp, createAndShowMainWindow)
+	ui.InstallSystray(myApp, createAndShowMainWindow, saveWindowGeometry, myServices)
 
-	// 4. Crear y mostrar la ventana principal por primera vez.
-	createAndShowMainWindow()
+	// 5. Crear y mostrar la ventana principal por primera vez, con los archivos
+	//    recibidos en la línea de comandos (p.ej. al usar "Abrir con"). Si el
+	//    usuario prefiere arrancar minimizado, solo se muestra si hay archivos.
+	wd, _ := os.Getwd()
+	files := fileArgs(os.Args[1:], wd)
+	startMinimized := myServices.Settings.Section(settings.AppSection).Bool(ui.SettingStartMinimized, false)
+	if !startMinimized || len(files) > 0 {
+		createAndShowMainWindow()
+		myLayout.OpenFiles(files)
+	}
+
+	// 6. Atender a las instancias que se lanzaron durante el arranque y, si está
+	//    activada, a la API local de automatización.
+	openForwardedArgs()
+	if api, err := automation.Start(myServices.Settings, automationHost{}); err != nil {
+		slog.Error("automation API unavailable", "err", err)
+	} else if api != nil {
+		defer api.Close()
+	}
 
-	// 5. Ejecutar el bucle principal de la aplicación.
+	// Al salir (p.ej. desde la bandeja) con la ventana abierta, su sesión se
+	// guarda aquí; si estaba cerrada, ya se guardó al cerrarla. El tamaño y la
+	// posición no: cuando se llama, la ventana nativa ya no existe, así que la
+	// bandeja los guarda antes de salir (saveWindowGeometry). Las herramientas
+	// guardan lo que sus ventanas tengan pendiente con ToolContext.Exit.
+	myApp.Lifecycle().SetOnStopped(func() {
+		myServices.Exit.Run()
+		if myLayout != nil {
+			myLayout.SaveSession()
+		}
+	})
+
+	// 7. Ejecutar el bucle principal de la aplicación.
 	myApp.Run()
 }
 
 // createAndShowMainWindow encapsula toda la lógica para crear y configurar la ventana.
 func createAndShowMainWindow() {
-	// Si la ventana ya existe y es visible, simplemente la enfocamos y salimos.
+	// Si la ventana ya existe, simplemente la mostramos, la enfocamos y salimos.
 	if myWindow != nil {
+		myWindow.Show()
 		myWindow.RequestFocus()
 		return
 	}
 
 	// Creamos una nueva ventana.
-	w := myApp.NewWindow("Toolbox - Windows Utilities")
+	w := myApp.NewWindow(i18n.T("Toolbox - Windows Utilities"))
 	myWindow = w // La asignamos a nuestra variable global.
 
-	w.Resize(fyne.NewSize(1050, 600))
+	// Restauramos el tamaño de la sesión anterior (la posición, tras Show()).
+	w.Resize(ui.SavedWindowSize(myServices.Settings, fyne.NewSize(1050, 600)))
 
 	// ¡¡NO LLAMAR A SetMaster()!!
 	// Al no tener una ventana maestra, el ciclo de vida de la app no está atado a esta ventana.
 	// Fyne no cerrará la app si la bandeja del sistema está activa.
 
-	// Construimos el layout principal y obtenemos la función para configurar los callbacks.
-	mainLayout, setupCallbacks := ui.CreateAppLayout()
-	w.SetContent(mainLayout)
-
-	// Configuramos los callbacks (como OnDropped) para esta ventana específica.
-	setupCallbacks(w)
+	// Construimos el layout principal para esta ventana. CreateAppLayout también
+	// configura sus callbacks (como OnDropped).
+	layout := ui.CreateAppLayout(w, myServices)
+	myLayout = layout
+	w.SetContent(layout.Content)
 
-	// Interceptamos el cierre de la ventana.
+	// Interceptamos el cierre de la ventana para guardar su tamaño y posición
+	// mientras la ventana nativa todavía existe.
 	w.SetCloseIntercept(func() {
+		saveWindowGeometry()
 		w.Close() // Ahora esto es seguro. Cierra la ventana pero no la app.
 	})
 
-	// Cuando la ventana se cierre (después de w.Close()), limpiamos nuestra
-	// referencia a ella y le pedimos al recolector de basura que se ejecute.
+	// Cuando la ventana se cierre (después de w.Close()), avisamos a las herramientas
+	// (Deactivate y Dispose), las descargamos y limpiamos nuestra referencia.
 	w.SetOnClosed(func() {
+		layout.Dispose()
 		myWindow = nil // Eliminamos la referencia.
-		runtime.GC()   // Sugerimos una recolección de basura.
+		myLayout = nil
 	})
 
 	w.Show()
+	ui.RestoreWindowPosition(w, myServices.Settings)
+}
+
+// forwarded guarda las peticiones de otras instancias que llegan antes de que la
+// aplicación y su ventana estén listas.
+var forwarded struct {
+	sync.Mutex
+	ready   bool
+	pending []instance.Request
+}
+
+// handleForwardedArgs recibe los argumentos de una segunda instancia: muestra la
+// ventana y abre en ella los archivos indicados. Durante el arranque solo los guarda.
+func handleForwardedArgs(req instance.Request) {
+	forwarded.Lock()
+	if !forwarded.ready {
+		forwarded.pending = append(forwarded.pending, req)
+		forwarded.Unlock()
+		return
+	}
+	forwarded.Unlock()
+	openInWindow(fileArgs(req.Args, req.Dir))
+}
+
+// openForwardedArgs abre las peticiones guardadas durante el arranque; desde ese
+// momento, las nuevas se abren en cuanto llegan.
+func openForwardedArgs() {
+	forwarded.Lock()
+	pending := forwarded.pending
+	forwarded.pending, forwarded.ready = nil, true
+	forwarded.Unlock()
+	for _, req := range pending {
+		openInWindow(fileArgs(req.Args, req.Dir))
+	}
+}
+
+// openInWindow muestra la ventana y abre en ella paths. Puede llamarse desde
+// cualquier goroutine.
+func openInWindow(paths []string) {
+	fyne.Do(func() {
+		createAndShowMainWindow()
+		myLayout.OpenFiles(paths)
+	})
+}
+
+// automationHost da a la API de automatización acceso a la aplicación.
+type automationHost struct{}
+
+func (automationHost) Open(paths []string) {
+	openInWindow(paths)
+}
+
+func (automationHost) Context(name string) *tools.ToolContext {
+	return ui.BackgroundContext(myServices, name)
+}
+
+// Tool abre la ventana, si hace falta, y muestra en ella la herramienta name.
+func (automationHost) Tool(name string) (tool tools.Tool, ctx *tools.ToolContext, err error) {
+	fyne.DoAndWait(func() {
+		createAndShowMainWindow()
+		tool, ctx, err = myLayout.OpenTool(name)
+	})
+	return tool, ctx, err
+}
+
+// fileArgs devuelve los archivos existentes de args como rutas absolutas, resolviendo
+// las relativas respecto a dir. Ignora las opciones ("-x") y lo que no sea un archivo.
+func fileArgs(args []string, dir string) []string {
+	var paths []string
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "-") {
+			continue
+		}
+		if !filepath.IsAbs(arg) {
+			arg = filepath.Join(dir, arg)
+		}
+		if info, err := os.Stat(arg); err != nil || info.IsDir() {
+			slog.Warn("ignoring argument, not a file", "arg", arg)
+			continue
+		}
+		paths = append(paths, arg)
+	}
+	return paths
+}
+
+// saveWindowGeometry guarda el tamaño y la posición de la ventana principal, si
+// está abierta. Se llama al cerrarla y al salir desde la bandeja.
+func saveWindowGeometry() {
+	if myWindow != nil {
+		ui.SaveWindowGeometry(myWindow, myServices.Settings)
+	}
 }
@@ -0,0 +1,290 @@
+// Package settings guarda los ajustes persistentes de la aplicación en
+// UserConfigDir()/MultiTool/settings.json.
+//
+// Los ajustes se agrupan en secciones: "app" para los ajustes generales y una por
+// herramienta, con el nombre de la herramienta. Cada sección declara un Schema
+// con sus campos, sus valores por defecto y su versión; la pantalla de ajustes
+// se construye a partir de esos esquemas.
+package settings
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// fileVersion es la versión del formato de settings.json.
+const fileVersion = 1
+
+// AppSection es la sección de los ajustes generales de la aplicación.
+const AppSection = "app"
+
+// Kind es el tipo de un campo.
+type Kind int
+
+const (
+	String Kind = iota
+	Bool
+	Choice   // Uno de Field.Options.
+	Folder   // Ruta de una carpeta; la pantalla de ajustes ofrece un selector.
+	Shortcut // Combinación de teclas, como "Ctrl+Shift+O".
+)
+
+// Field describe un ajuste de una sección.
+type Field struct {
+	Key         string
+	Label       string
+	Description string
+	Kind        Kind
+	Default     any // string para String, Choice, Folder y Shortcut; bool para Bool.
+	Options     []string
+
+	// OptionLabels es el texto que se muestra para cada opción de un Choice. Las
+	// opciones sin entrada se muestran tal cual.
+	OptionLabels map[string]string
+}
+
+// Schema describe una sección: sus campos y la versión de su formato.
+type Schema struct {
+	Section string
+	Title   string
+	Version int
+	Fields  []Field
+
+	// Migrate actualiza los valores guardados con una versión anterior (from)
+	// antes de usarlos. Puede ser nil.
+	Migrate func(from int, values map[string]any)
+}
+
+// fileData es el contenido de settings.json.
+type fileData struct {
+	Version  int                       `json:"version"`
+	Schemas  map[string]int            `json:"schemas"` // Versión de cada sección.
+	Sections map[string]map[string]any `json:"sections"`
+}
+
+// Store es el almacén de ajustes. Es seguro para uso concurrente.
+type Store struct {
+	path     string
+	readOnly bool // Los cambios no se escriben; ver Open.
+
+	mu        sync.Mutex
+	data      fileData
+	schemas   map[string]Schema
+	order     []string
+	listeners map[int]func(section, key string)
+	nextID    int
+}
+
+// Open carga los ajustes de path. Si el archivo no existe, empieza vacío.
+//
+// Ante un error siempre devuelve un Store utilizable, pero sin perder el archivo
+// del usuario: si está dañado, se renombra a settings.json.bak y se empieza con
+// los valores por defecto; si no se puede leer o lo escribió una versión más
+// nueva, el Store es de solo lectura y los cambios duran solo esta sesión.
+func Open(path string) (*Store, error) {
+	s := &Store{
+		path:      path,
+		schemas:   make(map[string]Schema),
+		listeners: make(map[int]func(section, key string)),
+		data: fileData{
+			Version:  fileVersion,
+			Schemas:  make(map[string]int),
+			Sections: make(map[string]map[string]any),
+		},
+	}
+
+	raw, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		s.readOnly = true
+		return s, fmt.Errorf("%w; changes will not be saved", err)
+	}
+	var data fileData
+	if err := json.Unmarshal(raw, &data); err != nil {
+		err = fmt.Errorf("parse %s: %w", filepath.Base(path), err)
+		backup := path + ".bak"
+		if renameErr := os.Rename(path, backup); renameErr != nil {
+			s.readOnly = true
+			return s, fmt.Errorf("%w; changes will not be saved", err)
+		}
+		return s, fmt.Errorf("%w; the file was moved to %s", err, filepath.Base(backup))
+	}
+	if data.Version > fileVersion {
+		// Usamos lo que entendamos, pero sin sobrescribir el formato nuevo.
+		s.readOnly = true
+		err = fmt.Errorf("%s was written by a newer version (format %d); changes will not be saved", filepath.Base(path), data.Version)
+	}
+	if data.Schemas != nil {
+		s.data.Schemas = data.Schemas
+	}
+	if data.Sections != nil {
+		s.data.Sections = data.Sections
+	}
+	return s, err
+}
+
+// Register declara el esquema de una sección y migra sus valores guardados si se
+// escribieron con una versión anterior.
+func (s *Store) Register(schema Schema) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.schemas[schema.Section]; !exists {
+		s.order = append(s.order, schema.Section)
+	}
+	s.schemas[schema.Section] = schema
+
+	stored, ok := s.data.Schemas[schema.Section]
+	s.data.Schemas[schema.Section] = schema.Version
+	if values := s.data.Sections[schema.Section]; ok && stored < schema.Version && values != nil && schema.Migrate != nil {
+		schema.Migrate(stored, values)
+		if err := s.saveLocked(); err != nil {
+			slog.Error("failed to save migrated settings", "section", schema.Section, "err", err)
+		}
+	}
+}
+
+// Schemas devuelve los esquemas registrados, con "app" primero y el resto en
+// orden de registro.
+func (s *Store) Schemas() []Schema {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result := make([]Schema, 0, len(s.order))
+	for _, name := range s.order {
+		result = append(result, s.schemas[name])
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Section == AppSection && result[j].Section != AppSection })
+	return result
+}
+
+// Section devuelve la vista de una sección. La sección no necesita tener esquema.
+func (s *Store) Section(name string) *Section {
+	return &Section{store: s, name: name}
+}
+
+// OnChange registra fn para que se llame (desde la goroutine que cambió el valor)
+// cada vez que cambie un ajuste. La función devuelta anula la suscripción.
+func (s *Store) OnChange(fn func(section, key string)) (remove func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := s.nextID
+	s.nextID++
+	s.listeners[id] = fn
+	return func() {
+		s.mu.Lock()
+		delete(s.listeners, id)
+		s.mu.Unlock()
+	}
+}
+
+// get devuelve el valor guardado, el valor por defecto del esquema o nil.
+func (s *Store) get(section, key string) any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if v, ok := s.data.Sections[section][key]; ok {
+		return v
+	}
+	for _, f := range s.schemas[section].Fields {
+		if f.Key == key {
+			return f.Default
+		}
+	}
+	return nil
+}
+
+// set guarda un valor y escribe el archivo.
+func (s *Store) set(section, key string, value any) {
+	s.mu.Lock()
+	values := s.data.Sections[section]
+	if values == nil {
+		values = make(map[string]any)
+		s.data.Sections[section] = values
+	}
+	if old, ok := values[key]; ok && old == value {
+		s.mu.Unlock()
+		return
+	}
+	values[key] = value
+	err := s.saveLocked()
+	listeners := make([]func(string, string), 0, len(s.listeners))
+	for _, fn := range s.listeners {
+		listeners = append(listeners, fn)
+	}
+	s.mu.Unlock()
+
+	if err != nil {
+		slog.Error("failed to save settings", "path", s.path, "err", err)
+	}
+	for _, fn := range listeners {
+		fn(section, key)
+	}
+}
+
+// saveLocked escribe el archivo de forma atómica (archivo temporal + rename). Si
+// el almacén es de solo lectura, no hace nada.
+func (s *Store) saveLocked() error {
+	if s.readOnly {
+		return nil
+	}
+	raw, err := json.MarshalIndent(s.data, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, raw, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
+
+// Section es la vista de una sección del almacén. Implementa tools.Settings.
+type Section struct {
+	store *Store
+	name  string
+}
+
+// String devuelve el valor de key, su valor por defecto o fallback.
+func (sec *Section) String(key, fallback string) string {
+	if v, ok := sec.store.get(sec.name, key).(string); ok {
+		return v
+	}
+	return fallback
+}
+
+// SetString guarda key.
+func (sec *Section) SetString(key, value string) {
+	sec.store.set(sec.name, key, value)
+}
+
+// Bool devuelve el valor de key, su valor por defecto o fallback.
+func (sec *Section) Bool(key string, fallback bool) bool {
+	if v, ok := sec.store.get(sec.name, key).(bool); ok {
+		return v
+	}
+	return fallback
+}
+
+// SetBool guarda key.
+func (sec *Section) SetBool(key string, value bool) {
+	sec.store.set(sec.name, key, value)
+}
+
+// Float devuelve el valor numérico de key, su valor por defecto o fallback.
+func (sec *Section) Float(key string, fallback float64) float64 {
+	if v, ok := sec.store.get(sec.name, key).(float64); ok {
+		return v
+	}
+	return fallback
+}
+
+// SetFloat guarda key.
+func (sec *Section) SetFloat(key string, value float64) {
+	sec.store.set(sec.name, key, value)
+}
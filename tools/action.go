@@ -0,0 +1,22 @@
+package tools
+
+// Action es una acción concreta que una herramienta ofrece en la paleta de
+// comandos (Ctrl+K), como "Aplicar perfil Cable" o "Fusionar PDFs".
+type Action struct {
+	ID          string // Se pasa a ActionHandler.RunAction al elegir la acción.
+	Title       string // Texto mostrado y buscado, ya traducido.
+	Description string // Detalle opcional, también buscado.
+}
+
+// ActionList devuelve las acciones de una herramienta. Se declara en
+// ToolDescriptor.Actions y se llama cada vez que se abre la paleta, de modo que
+// la lista puede depender de datos guardados (perfiles, ajustes...), pero no de
+// la instancia de la herramienta: abrir la paleta no construye herramientas.
+type ActionList func() []Action
+
+// ActionHandler lo implementan las herramientas que declaran acciones. La paleta
+// construye la herramienta y la muestra al elegir una, y después llama a
+// RunAction con su ID, así que la UI construida en GetUI ya existe.
+type ActionHandler interface {
+	RunAction(id string)
+}
@@ -0,0 +1,10 @@
+// Package builtin enlaza las herramientas que se distribuyen con MultiTool.
+//
+// Cada herramienta se registra sola desde su init(); este paquete solo las
+// importa, una por archivo, para que cada una pueda excluirse con una etiqueta
+// de compilación y obtener binarios reducidos:
+//
+//	go build -tags no_networkswitcher .
+//
+// Para añadir una herramienta basta con crear aquí un archivo nuevo.
+package builtin
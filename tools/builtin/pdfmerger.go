@@ -0,0 +1,5 @@
+//go:build !no_pdfmerger
+
+package builtin
+
+import _ "github.com/Lec7ral/MultiTool/tools/files/pdfmerger"
@@ -0,0 +1,39 @@
+package tools
+
+// Catálogo global de herramientas. Cada paquete de herramienta añade aquí su
+// descriptor estático desde init(), de modo que basta con importarlo (ver el
+// paquete tools/builtin) para que aparezca en la aplicación.
+var (
+	catalogTools      []ToolDescriptor
+	catalogCategories []Category
+)
+
+// Register añade un descriptor al catálogo global. Está pensado para llamarse
+// desde la función init() del paquete de la herramienta.
+func Register(descriptor ToolDescriptor) {
+	catalogTools = append(catalogTools, descriptor)
+}
+
+// RegisterCategory añade una categoría al catálogo global, para herramientas que
+// quieran definir el orden, icono o descripción de una categoría propia.
+func RegisterCategory(category Category) {
+	catalogCategories = append(catalogCategories, category)
+}
+
+// Descriptors devuelve los descriptores del catálogo global, en orden de registro.
+func Descriptors() []ToolDescriptor {
+	return append([]ToolDescriptor(nil), catalogTools...)
+}
+
+// NewDefaultRegistry crea un registro con todas las categorías y herramientas del
+// catálogo global.
+func NewDefaultRegistry() *ToolRegistry {
+	tr := NewToolRegistry()
+	for _, c := range catalogCategories {
+		tr.RegisterCategory(c)
+	}
+	for _, d := range catalogTools {
+		tr.Register(d)
+	}
+	return tr
+}
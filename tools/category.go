@@ -0,0 +1,109 @@
+package tools
+
+import (
+	"embed"
+	"log/slog"
+	"sort"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/theme"
+	"github.com/Lec7ral/MultiTool/i18n"
+)
+
+// locales contiene las traducciones de los nombres de las categorías por defecto.
+//
+//go:embed locales
+var locales embed.FS
+
+func init() {
+	if err := i18n.AddCatalogFS(locales, "locales"); err != nil {
+		slog.Error("failed to load category translations", "err", err)
+	}
+}
+
+// Category describe una pestaña de nivel superior que agrupa herramientas.
+type Category struct {
+	Name        string
+	Order       int // Las categorías se muestran de menor a mayor Order.
+	Icon        fyne.Resource
+	Description string // Se muestra, traducida, en la cabecera de la pestaña.
+}
+
+// defaultCategories devuelve las categorías conocidas de antemano. Sus iconos son
+// recursos del tema que Fyne colorea al dibujarlos, así que siguen al tema activo.
+func defaultCategories() []Category {
+	return []Category{
+		{Name: "System", Order: 10, Icon: theme.SettingsIcon(), Description: "System utilities"},
+		{Name: "Files", Order: 20, Icon: theme.FolderIcon(), Description: "File and document tools"},
+		{Name: "Text", Order: 30, Icon: theme.DocumentIcon(), Description: "Text processing tools"},
+		{Name: "Network", Order: 40, Icon: theme.ComputerIcon(), Description: "Network configuration tools"},
+	}
+}
+
+// RegisterCategory registra (o reemplaza) una categoría.
+func (tr *ToolRegistry) RegisterCategory(category Category) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	tr.registerCategory(category)
+}
+
+// registerCategory es RegisterCategory con el registro ya bloqueado.
+func (tr *ToolRegistry) registerCategory(category Category) {
+	if category.Icon == nil {
+		category.Icon = theme.ListIcon()
+	}
+	tr.categories[category.Name] = category
+}
+
+// ensureCategory crea automáticamente una categoría desconocida, colocándola
+// detrás de todas las existentes. El registro debe estar bloqueado.
+func (tr *ToolRegistry) ensureCategory(name string) {
+	if _, ok := tr.categories[name]; ok {
+		return
+	}
+	order := 0
+	for _, c := range tr.categories {
+		if c.Order >= order {
+			order = c.Order + 10
+		}
+	}
+	tr.registerCategory(Category{Name: name, Order: order})
+}
+
+// GetCategories devuelve las categorías que contienen al menos una herramienta,
+// ordenadas por Order (y por nombre en caso de empate).
+func (tr *ToolRegistry) GetCategories() []Category {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	used := make(map[string]bool)
+	for _, d := range tr.toolDescriptors {
+		used[d.Category] = true
+	}
+
+	result := make([]Category, 0, len(used))
+	for name, c := range tr.categories {
+		if used[name] {
+			result = append(result, c)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Order != result[j].Order {
+			return result[i].Order < result[j].Order
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// GetDescriptorsByCategory devuelve, en orden de registro, las herramientas de una categoría.
+func (tr *ToolRegistry) GetDescriptorsByCategory(category string) []ToolDescriptor {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	var result []ToolDescriptor
+	for _, name := range tr.order {
+		if d := tr.toolDescriptors[name]; d.Category == category {
+			result = append(result, d)
+		}
+	}
+	return result
+}
@@ -0,0 +1,33 @@
+package tools
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrUsage indica que un comando recibió argumentos incorrectos. La CLI lo
+// traduce al código de salida 2 y muestra la ayuda del comando.
+var ErrUsage = errors.New("invalid arguments")
+
+// Command es un subcomando de línea de comandos aportado por una herramienta, por
+// ejemplo "multitool pdf merge". Reutiliza el mismo backend que la interfaz gráfica.
+type Command struct {
+	Group   string // Primer nivel del comando, p.ej. "pdf".
+	Name    string // Segundo nivel, p.ej. "merge".
+	Usage   string // Sintaxis de los argumentos, p.ej. "-o out.pdf file.pdf[:pages]...".
+	Summary string
+
+	// Run ejecuta el comando. El resultado se imprime como JSON con --json; si no,
+	// se usa su método String() cuando lo tiene.
+	Run func(ctx context.Context, args []string) (any, error)
+}
+
+// RegisteredCommands devuelve los comandos de todas las herramientas del catálogo
+// global, en orden de registro.
+func RegisteredCommands() []Command {
+	var result []Command
+	for _, d := range Descriptors() {
+		result = append(result, d.Commands...)
+	}
+	return result
+}
@@ -0,0 +1,67 @@
+package tools
+
+import (
+	"log/slog"
+
+	"fyne.io/fyne/v2"
+	"github.com/Lec7ral/MultiTool/jobs"
+	"github.com/Lec7ral/MultiTool/undo"
+)
+
+// StatusBar es la barra de estado compartida de la ventana principal.
+type StatusBar interface {
+	SetStatus(text string)
+}
+
+// Notifier envía notificaciones al usuario (normalmente notificaciones del sistema).
+type Notifier interface {
+	Notify(title, content string)
+}
+
+// Settings es un almacén clave/valor con el espacio de nombres de una herramienta.
+type Settings interface {
+	String(key, fallback string) string
+	SetString(key, value string)
+	Bool(key string, fallback bool) bool
+	SetBool(key string, value bool)
+}
+
+// ToolContext agrupa los servicios de la aplicación que recibe cada herramienta en GetUI.
+type ToolContext struct {
+	ToolName string        // Nombre de la herramienta que recibe el contexto.
+	Window   fyne.Window   // Ventana que aloja la herramienta (padre de los diálogos). Nil en la bandeja.
+	Status   StatusBar     // Barra de estado compartida.
+	Notifier Notifier      // Notificaciones del sistema.
+	Settings Settings      // Ajustes propios de la herramienta.
+	Logger   *slog.Logger  // Logger con el nombre de la herramienta ya asociado.
+	Jobs     *jobs.Manager // Tareas en segundo plano compartidas por toda la aplicación.
+	Systray  SystrayMenu   // Menú de la bandeja del sistema.
+	Undo     *undo.Stack   // Historial de deshacer de la herramienta (Ctrl+Z y Ctrl+Y).
+	Exit     ExitHooks     // Lo que hay que hacer al terminar la aplicación.
+
+	// OnPanic lo rellena la interfaz: recibe el pánico de un callback protegido
+	// con Safe o RunSafely y muestra el panel de error de la herramienta. Puede ser nil.
+	OnPanic func(err error)
+}
+
+// Safe devuelve fn protegida contra pánicos, para usarla como callback de la
+// interfaz: el OnTapped de un botón, la función que se pasa a fyne.Do... Un
+// pánico en fn se registra y la herramienta pasa a mostrar su panel de error, en
+// lugar de cerrar toda la aplicación.
+func (c *ToolContext) Safe(fn func()) func() {
+	return func() { c.RunSafely(fn) }
+}
+
+// RunSafely ejecuta fn protegida como en Safe. Sirve para los callbacks con
+// argumentos, como los de los diálogos o el OnChanged de una entrada.
+func (c *ToolContext) RunSafely(fn func()) {
+	if err := Safely(c.ToolName, fn); err != nil && c.OnPanic != nil {
+		c.OnPanic(err)
+	}
+}
+
+// Submit lanza fn como tarea en segundo plano a nombre de la herramienta. El
+// resultado aparece en el panel de tareas de la barra de estado.
+func (c *ToolContext) Submit(title string, fn jobs.Func) *jobs.Job {
+	return c.Jobs.Submit(c.ToolName, title, fn)
+}
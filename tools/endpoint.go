@@ -0,0 +1,52 @@
+package tools
+
+import (
+	"errors"
+	"net/http"
+)
+
+// ErrNotFound indica que no existe lo que se pidió (un perfil, un archivo...). La
+// API de automatización lo traduce a 404.
+var ErrNotFound = errors.New("not found")
+
+// Endpoint es una ruta de la API local de automatización aportada por una
+// herramienta, por ejemplo "POST /v1/net/profiles/{name}/apply". Como Command,
+// reutiliza el mismo backend que la interfaz gráfica.
+//
+// El resultado de la petición se envía como JSON. Un error que envuelva ErrUsage
+// se responde con 400 y uno que envuelva ErrNotFound, con 404.
+type Endpoint struct {
+	Method  string // Método HTTP, p.ej. http.MethodGet.
+	Path    string // Ruta con comodines de net/http, p.ej. "/v1/net/profiles/{name}".
+	Summary string
+
+	// Handle atiende la petición. ctx es un contexto sin ventana de la
+	// herramienta, como el de la bandeja: el trabajo largo se lanza con
+	// ctx.Submit, para que aparezca en el panel de tareas.
+	Handle func(ctx *ToolContext, r *http.Request) (any, error)
+
+	// HandleTool sustituye a Handle en las rutas que trabajan con la herramienta
+	// abierta, como "fusionar la lista del PDF Merger": la ventana se abre y
+	// muestra la herramienta si hace falta, y tool y ctx son los suyos. Se llama
+	// desde la goroutine de la petición, así que el estado de la herramienta se
+	// toca con fyne.DoAndWait.
+	HandleTool func(tool Tool, ctx *ToolContext, r *http.Request) (any, error)
+}
+
+// ToolEndpoint es una ruta junto con el nombre de la herramienta que la aporta.
+type ToolEndpoint struct {
+	Tool string
+	Endpoint
+}
+
+// RegisteredEndpoints devuelve las rutas de todas las herramientas del catálogo
+// global, en orden de registro.
+func RegisteredEndpoints() []ToolEndpoint {
+	var result []ToolEndpoint
+	for _, d := range Descriptors() {
+		for _, e := range d.Endpoints {
+			result = append(result, ToolEndpoint{Tool: d.Name, Endpoint: e})
+		}
+	}
+	return result
+}
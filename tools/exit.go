@@ -0,0 +1,11 @@
+package tools
+
+// ExitHooks son las funciones que la aplicación ejecuta al terminar, también
+// cuando se sale desde la bandeja con ventanas abiertas. Sirven para guardar lo
+// que una ventana de la herramienta tiene todavía en memoria.
+type ExitHooks interface {
+	// OnExit registra fn y devuelve la función que la quita, que hay que llamar
+	// cuando fn deja de hacer falta (al cerrar la ventana, por ejemplo). fn puede
+	// ejecutarse en cualquier goroutine, después de cerrarse las ventanas.
+	OnExit(fn func()) (remove func())
+}
@@ -0,0 +1,56 @@
+package tools
+
+import (
+	"mime"
+	"path/filepath"
+	"strings"
+)
+
+// FileDropper is an optional interface for tools that can handle dropped files.
+// Los tipos de archivo que acepta se declaran en ToolDescriptor.AcceptedTypes,
+// para poder elegir la herramienta sin construirla.
+type FileDropper interface {
+	OnFilesDropped(files []string)
+}
+
+// Accepts indica si el archivo path coincide con alguno de los tipos aceptados.
+func Accepts(accepted []string, path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	mimeType := ""
+	if ext != "" {
+		mimeType, _, _ = strings.Cut(mime.TypeByExtension(ext), ";")
+	}
+
+	for _, a := range accepted {
+		a = strings.ToLower(strings.TrimSpace(a))
+		switch {
+		case strings.HasPrefix(a, "."):
+			if a == ext {
+				return true
+			}
+		case strings.HasSuffix(a, "/*"):
+			if mimeType != "" && strings.HasPrefix(mimeType, strings.TrimSuffix(a, "*")) {
+				return true
+			}
+		case a == "*" || a == "*/*":
+			return true
+		default:
+			if mimeType != "" && a == mimeType {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// FilterAccepted separa files en los que coinciden con types y los que no.
+func FilterAccepted(types []string, files []string) (accepted, rejected []string) {
+	for _, f := range files {
+		if Accepts(types, f) {
+			accepted = append(accepted, f)
+		} else {
+			rejected = append(rejected, f)
+		}
+	}
+	return accepted, rejected
+}
@@ -0,0 +1,115 @@
+package pdfmerger
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+
+	"fyne.io/fyne/v2"
+	"github.com/Lec7ral/MultiTool/i18n"
+	"github.com/Lec7ral/MultiTool/jobs"
+	"github.com/Lec7ral/MultiTool/tools"
+)
+
+// --- Automation API ---
+
+var endpoints = []tools.Endpoint{
+	{
+		Method:  http.MethodPost,
+		Path:    "/v1/pdf/merge",
+		Summary: `Merge PDF files: {"files": ["/abs/a.pdf:1-3", ...], "output": "/abs/out.pdf"}`,
+		Handle:  handleMerge,
+	},
+	{
+		Method:     http.MethodPost,
+		Path:       "/v1/pdf/list/merge",
+		Summary:    `Merge the files in the merger's list, e.g. those queued with /v1/open: {"output": "/abs/out.pdf"}`,
+		HandleTool: handleMergeList,
+	},
+}
+
+// mergeRequest is the body of POST /v1/pdf/merge and /v1/pdf/list/merge.
+// Files use the same "path[:pages]" syntax as the command line.
+type mergeRequest struct {
+	Files  []string `json:"files"`
+	Output string   `json:"output"`
+}
+
+// decodeMerge reads a merge request and checks its output path. The server's
+// working directory means nothing to the caller, so paths must be absolute.
+func decodeMerge(r *http.Request) (mergeRequest, error) {
+	var req mergeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return req, fmt.Errorf("%w: %v", tools.ErrUsage, err)
+	}
+	if req.Output == "" {
+		return req, fmt.Errorf("%w: an output file is required", tools.ErrUsage)
+	}
+	if !filepath.IsAbs(req.Output) {
+		return req, fmt.Errorf("%w: output %q is not an absolute path", tools.ErrUsage, req.Output)
+	}
+	return req, nil
+}
+
+// handleMerge merges the given files, leaving the merger's list alone. Like
+// every merge it runs as a background job and answers when the job is done.
+func handleMerge(ctx *tools.ToolContext, r *http.Request) (any, error) {
+	req, err := decodeMerge(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.Files) == 0 {
+		return nil, fmt.Errorf("%w: at least one input is required", tools.ErrUsage)
+	}
+	files := make([]pdfFileItem, 0, len(req.Files))
+	for _, arg := range req.Files {
+		item := parseFileArg(arg)
+		if !filepath.IsAbs(item.Path) {
+			return nil, fmt.Errorf("%w: %q is not an absolute path", tools.ErrUsage, item.Path)
+		}
+		files = append(files, item)
+	}
+	job := ctx.Submit(i18n.T("Merge into %s", filepath.Base(req.Output)), func(jobCtx context.Context, r jobs.Reporter) error {
+		return mergePDFs(jobCtx, files, req.Output, r)
+	})
+	if err := job.Wait(); err != nil {
+		return nil, err
+	}
+	return mergeResult{Output: req.Output, Files: len(files)}, nil
+}
+
+// handleMergeList runs the merger's main action on its current list, as the
+// "Merge PDFs" button does, and answers when the job is done.
+func handleMergeList(tool tools.Tool, ctx *tools.ToolContext, r *http.Request) (any, error) {
+	t, ok := tool.(*PDFMergerTool)
+	if !ok {
+		return nil, fmt.Errorf("unexpected tool %T", tool)
+	}
+	req, err := decodeMerge(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var job *jobs.Job
+	var count int
+	fyne.DoAndWait(func() {
+		// Safely: a panic here does not reach the HTTP server, which runs on another goroutine.
+		err = tools.Safely(ctx.ToolName, func() {
+			if count = len(t.pdfFiles); count > 0 {
+				job = t.submitMerge(ctx, req.Output, nil)
+			}
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	if job == nil {
+		return nil, fmt.Errorf("%w: the merge list is empty", tools.ErrUsage)
+	}
+	if err := job.Wait(); err != nil {
+		return nil, err
+	}
+	return mergeResult{Output: req.Output, Files: count}, nil
+}
@@ -0,0 +1,81 @@
+package pdfmerger
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/Lec7ral/MultiTool/i18n"
+	"github.com/Lec7ral/MultiTool/jobs"
+	"github.com/Lec7ral/MultiTool/tools"
+)
+
+// --- Command Line ---
+
+var commands = []tools.Command{
+	{
+		Group:   "pdf",
+		Name:    "merge",
+		Usage:   "-o out.pdf file.pdf[:pages]...",
+		Summary: "Merge PDF files, optionally selecting pages (e.g. a.pdf:1-3,!2)",
+		Run:     runMergeCommand,
+	},
+}
+
+// mergeResult is the output of "pdf merge".
+type mergeResult struct {
+	Output string `json:"output"`
+	Files  int    `json:"files"`
+}
+
+func (r mergeResult) String() string {
+	return i18n.T("Merged %d file(s) into %s", r.Files, r.Output)
+}
+
+func runMergeCommand(ctx context.Context, args []string) (any, error) {
+	fs := flag.NewFlagSet("pdf merge", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	out := fs.String("o", "", "output file")
+	if err := fs.Parse(args); err != nil {
+		return nil, fmt.Errorf("%w: %v", tools.ErrUsage, err)
+	}
+	if *out == "" || fs.NArg() == 0 {
+		return nil, fmt.Errorf("%w: an output file (-o) and at least one input are required", tools.ErrUsage)
+	}
+
+	files := make([]pdfFileItem, 0, fs.NArg())
+	for _, arg := range fs.Args() {
+		files = append(files, parseFileArg(arg))
+	}
+	if err := mergePDFs(ctx, files, *out, jobs.Discard); err != nil {
+		return nil, err
+	}
+	return mergeResult{Output: *out, Files: len(files)}, nil
+}
+
+// parseFileArg splits "path[:pages]". What follows the last colon is a page
+// selection only if it looks like one ("1-3,5", "!2"), so a colon that is part
+// of the name ("/tmp/a:b.pdf", "C:\docs\a.pdf") stays in the path. An argument
+// naming an existing file is always taken whole.
+func parseFileArg(arg string) pdfFileItem {
+	if _, err := os.Stat(arg); err == nil {
+		return pdfFileItem{Path: arg}
+	}
+	i := strings.LastIndex(arg, ":")
+	if i > 0 && isPageSelection(arg[i+1:]) {
+		return pdfFileItem{Path: arg[:i], PageRange: arg[i+1:]}
+	}
+	return pdfFileItem{Path: arg}
+}
+
+// isPageSelection reports whether s uses only the page selection syntax:
+// digits, '-', ',', '!' and spaces.
+func isPageSelection(s string) bool {
+	if strings.TrimSpace(s) == "" {
+		return false
+	}
+	return strings.Trim(s, "0123456789-,! ") == ""
+}
@@ -1,10 +1,15 @@
 package pdfmerger
 
 import (
+	"context"
+	"embed"
 	"errors"
 	"fmt"
+	"log/slog"
+	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"fyne.io/fyne/v2"
@@ -12,6 +17,13 @@ import (
 	"fyne.io/fyne/v2/dialog"
 	"fyne.io/fyne/v2/storage"
 	"fyne.io/fyne/v2/widget"
+	"github.com/Lec7ral/MultiTool/assets"
+	"github.com/Lec7ral/MultiTool/i18n"
+	"github.com/Lec7ral/MultiTool/jobs"
+	"github.com/Lec7ral/MultiTool/logging"
+	"github.com/Lec7ral/MultiTool/settings"
+	"github.com/Lec7ral/MultiTool/tools"
+	"github.com/Lec7ral/MultiTool/undo"
 	"github.com/pdfcpu/pdfcpu/pkg/api"
 )
 
@@ -42,11 +54,57 @@ type pdfFileItem struct {
 	PageCount int
 }
 
+// --- Registration ---
+var descriptor = tools.ToolDescriptor{
+	Name:        "PDF Merger",
+	Description: "Combine and reorder PDFs with page selection",
+	Category:    "Files",
+	Icon:        assets.Icon(assets.IconPDF),
+	Constructor: func() tools.Tool { return New() },
+	Commands:    commands,
+	Operations:  operations,
+	Endpoints:   endpoints,
+	Systray:     systrayItems,
+	Shortcuts:   shortcuts,
+	Actions:     actions,
+	// Files the merger takes from drag and drop and the command line.
+	AcceptedTypes: []string{".pdf", "application/pdf"},
+	Settings: []settings.Field{
+		{Key: settingOutputDir, Label: "Default output folder", Kind: settings.Folder,
+			Description: "Folder proposed by 'Save As...'"},
+	},
+}
+
+// Setting keys.
+const (
+	settingOutputDir = "outputDir"
+	settingRecent    = "recent" // Newline-separated paths of the last merged files, newest first.
+)
+
+// maxRecent is the number of merged files remembered for the system tray.
+const maxRecent = 5
+
+//go:embed locales
+var locales embed.FS
+
+func init() {
+	if err := i18n.AddCatalogFS(locales, "locales"); err != nil {
+		slog.Error("failed to load pdf merger translations", "err", err)
+	}
+	tools.Register(descriptor)
+}
+
 // --- Tool Definition ---
 type PDFMergerTool struct {
-	pdfFiles []pdfFileItem
-	fileList *widget.List
-	icon     fyne.Resource // Cache del icono
+	pdfFiles    []pdfFileItem
+	fileList    *widget.List
+	addBtn      *widget.Button
+	removeBtn   *widget.Button
+	moveUpBtn   *widget.Button
+	moveDownBtn *widget.Button
+	saveAsBtn   *widget.Button
+	mergeBtn    *widget.Button
+	ctx         *tools.ToolContext // Set by GetUI
 }
 
 func New() *PDFMergerTool {
@@ -57,28 +115,80 @@ func New() *PDFMergerTool {
 }
 
 func (t *PDFMergerTool) GetName() string {
-	return "PDF Merger"
+	return descriptor.Name
 }
 
 func (t *PDFMergerTool) GetDescription() string {
-	return "Combine and reorder PDFs with page selection"
+	return descriptor.Description
 }
 
 func (t *PDFMergerTool) GetCategory() string {
-	return "Files"
+	return descriptor.Category
 }
 
 func (t *PDFMergerTool) GetIcon() fyne.Resource {
-	// Cargar el icono solo una vez y cachearlo.
-	if t.icon == nil {
-		resource, err := fyne.LoadResourceFromPath("assets/pdf.svg")
-		if err != nil {
-			fyne.LogError("Failed to load pdf icon", err)
-			return nil
-		}
-		t.icon = resource
+	return descriptor.Icon
+}
+
+// CanEvict keeps the tool loaded while there are queued files that would be lost.
+func (t *PDFMergerTool) CanEvict() bool {
+	return len(t.pdfFiles) == 0
+}
+
+// Dispose drops the references to the UI built by GetUI.
+func (t *PDFMergerTool) Dispose() {
+	t.fileList = nil
+	t.addBtn = nil
+	t.removeBtn = nil
+	t.moveUpBtn = nil
+	t.moveDownBtn = nil
+	t.saveAsBtn = nil
+	t.mergeBtn = nil
+	t.ctx = nil
+}
+
+// actions offers the main buttons in the command palette.
+func actions() []tools.Action {
+	return []tools.Action{
+		{ID: tools.ShortcutOpen, Title: i18n.T("Add PDFs...")},
+		{ID: tools.ShortcutPrimary, Title: i18n.T("Merge PDFs")},
+	}
+}
+
+// RunAction presses the button behind one of the actions, which share their IDs
+// with the shortcuts.
+func (t *PDFMergerTool) RunAction(id string) {
+	t.RunShortcut(id)
+}
+
+// shortcuts are the keyboard shortcuts for the buttons, run by RunShortcut.
+var shortcuts = []tools.Shortcut{
+	{ID: tools.ShortcutOpen, Title: "Add PDFs..."},
+	{ID: tools.ShortcutPrimary, Title: "Merge PDFs"},
+	{ID: "saveAs", Title: "Save As...", Keys: "Ctrl+S"},
+	{ID: "remove", Title: "Remove", Keys: "Ctrl+Delete"},
+	{ID: "moveUp", Title: "Move Up", Keys: "Alt+Up"},
+	{ID: "moveDown", Title: "Move Down", Keys: "Alt+Down"},
+}
+
+// RunShortcut presses the button behind one of the shortcuts.
+func (t *PDFMergerTool) RunShortcut(id string) {
+	buttons := map[string]*widget.Button{
+		tools.ShortcutOpen:    t.addBtn,
+		tools.ShortcutPrimary: t.mergeBtn,
+		"saveAs":              t.saveAsBtn,
+		"remove":              t.removeBtn,
+		"moveUp":              t.moveUpBtn,
+		"moveDown":            t.moveDownBtn,
+	}
+	tap(buttons[id])
+}
+
+// tap presses b as if the user had clicked it.
+func tap(b *widget.Button) {
+	if b != nil && !b.Disabled() && b.OnTapped != nil {
+		b.OnTapped()
 	}
-	return t.icon
 }
 
 // OnFilesDropped is called by the app layout when files are dropped.
@@ -91,12 +201,8 @@ func (t *PDFMergerTool) OnFilesDropped(files []string) {
 			path = path[1:]
 		}
 
-		if filepath.Ext(path) == ".pdf" {
-			count, err := api.PageCountFile(filepath.FromSlash(path))
-			if err != nil {
-				fyne.LogError("Failed to count pages for "+path, err)
-			}
-			t.pdfFiles = append(t.pdfFiles, pdfFileItem{Path: path, PageCount: count})
+		if strings.EqualFold(filepath.Ext(path), ".pdf") {
+			t.pdfFiles = append(t.pdfFiles, t.newFileItem(path))
 		}
 	}
 	if t.fileList != nil {
@@ -104,18 +210,93 @@ func (t *PDFMergerTool) OnFilesDropped(files []string) {
 	}
 }
 
+// newFileItem builds a list item for path, counting its pages.
+func (t *PDFMergerTool) newFileItem(path string) pdfFileItem {
+	count, err := api.PageCountFile(filepath.FromSlash(path))
+	if err != nil {
+		if t.ctx != nil {
+			t.ctx.Logger.Error("failed to count pages", "path", path, "err", err)
+		} else {
+			slog.Error("failed to count pages", logging.ToolKey, descriptor.Name, "path", path, "err", err)
+		}
+	}
+	return pdfFileItem{Path: path, PageCount: count}
+}
+
+// --- List Edits ---
+
+// removeFile drops the file at index i from the merge list.
+func (t *PDFMergerTool) removeFile(i int) {
+	t.pdfFiles = append(t.pdfFiles[:i], t.pdfFiles[i+1:]...)
+	if t.fileList != nil {
+		t.fileList.UnselectAll()
+		t.fileList.Refresh()
+	}
+}
+
+// insertFile puts item back at index i and selects it.
+func (t *PDFMergerTool) insertFile(i int, item pdfFileItem) {
+	t.pdfFiles = slices.Insert(t.pdfFiles, i, item)
+	if t.fileList != nil {
+		t.fileList.Refresh()
+		t.fileList.Select(i)
+	}
+}
+
+// swapFiles exchanges the files at i and j and selects the one now at j.
+func (t *PDFMergerTool) swapFiles(i, j int) {
+	t.pdfFiles[i], t.pdfFiles[j] = t.pdfFiles[j], t.pdfFiles[i]
+	if t.fileList != nil {
+		t.fileList.Refresh()
+		t.fileList.Select(j)
+	}
+}
+
+// moveFile moves the file at from to the neighbouring position to, as an
+// undoable change.
+func (t *PDFMergerTool) moveFile(ctx *tools.ToolContext, from, to int) {
+	ctx.Undo.Do(undo.Command{
+		Title: i18n.T("Move %s", filepath.Base(t.pdfFiles[from].Path)),
+		Do:    func() { t.swapFiles(from, to) },
+		Undo:  func() { t.swapFiles(to, from) },
+	})
+}
+
+// submitMerge merges the current list into outFile as a background job. done,
+// if not nil, is called on the UI goroutine when the job finishes.
+func (t *PDFMergerTool) submitMerge(ctx *tools.ToolContext, outFile string, done func(error)) *jobs.Job {
+	// The job works on a copy so the list can keep being edited meanwhile.
+	files := append([]pdfFileItem(nil), t.pdfFiles...)
+	return ctx.Submit(i18n.T("Merge into %s", filepath.Base(outFile)), func(jobCtx context.Context, r jobs.Reporter) error {
+		err := mergePDFs(jobCtx, files, outFile, r)
+		fyne.Do(ctx.Safe(func() {
+			if err != nil {
+				ctx.Logger.Error("merge failed", "output", outFile, "err", err)
+			} else {
+				ctx.Status.SetStatus(i18n.T("PDFs merged into %s", outFile))
+				addRecent(ctx, outFile)
+			}
+			if done != nil {
+				done(err)
+			}
+		}))
+		return err
+	})
+}
+
 // --- Main UI ---
-func (t *PDFMergerTool) GetUI(window fyne.Window) fyne.CanvasObject {
+func (t *PDFMergerTool) GetUI(ctx *tools.ToolContext) fyne.CanvasObject {
+	t.ctx = ctx
 	var selectedIndex int = -1
 
-	statusLabel := widget.NewLabel("Arrastra y suelta archivos o usa 'Añadir PDFs'. Para seleccionar páginas, usa rangos (ej: 2-5), números sueltos (ej: 8), rangos abiertos (ej: 12-) o exclusiones (ej: !10).")
+	statusLabel := widget.NewLabel(i18n.T("Drag and drop files or use 'Add PDFs...'. To select pages, use ranges (e.g. 2-5), single pages (e.g. 8), open ranges (e.g. 12-) or exclusions (e.g. !10)."))
 
 	// --- File List with Page Range ---
 	t.fileList = widget.NewList(
 		func() int { return len(t.pdfFiles) },
 		func() fyne.CanvasObject {
 			pageEntry := newSizedEntry(150)
-			pageEntry.SetPlaceHolder("e.g., 1-5, !3")
+			pageEntry.SetPlaceHolder(i18n.T("e.g., 1-5, !3"))
 			return container.NewBorder(nil, nil, nil, pageEntry, widget.NewLabel("template"))
 		},
 		func(i widget.ListItemID, o fyne.CanvasObject) {
@@ -123,108 +304,125 @@ func (t *PDFMergerTool) GetUI(window fyne.Window) fyne.CanvasObject {
 			label := c.Objects[0].(*widget.Label)
 			labelText := filepath.Base(t.pdfFiles[i].Path)
 			if t.pdfFiles[i].PageCount > 0 {
-				labelText = fmt.Sprintf("%s (%d pages)", labelText, t.pdfFiles[i].PageCount)
+				labelText = i18n.T("%s (%d pages)", labelText, t.pdfFiles[i].PageCount)
 			}
 			label.SetText(labelText)
 
 			entry := c.Objects[1].(*sizedEntry)
 			entry.SetText(t.pdfFiles[i].PageRange)
 			entry.OnChanged = func(s string) {
-				t.pdfFiles[i].PageRange = s
+				ctx.RunSafely(func() { t.pdfFiles[i].PageRange = s })
 			}
 		},
 	)
 	t.fileList.OnSelected = func(id widget.ListItemID) { selectedIndex = id }
+	t.fileList.OnUnselected = func(widget.ListItemID) { selectedIndex = -1 }
 
 	// --- Action Buttons (Right Panel) ---
-	addBtn := widget.NewButton("Add PDFs...", func() {
+	// Callbacks go through ctx.Safe so a panic only takes down this tool.
+	t.addBtn = widget.NewButton(i18n.T("Add PDFs..."), ctx.Safe(func() {
 		fileDialog := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
 			if err != nil || reader == nil {
 				return
 			}
-			path := reader.URI().Path()
-			// On Windows, file URIs from Fyne can have a leading slash.
-			// We remove it to ensure compatibility with file system operations.
-			if len(path) > 2 && path[0] == '/' && path[2] == ':' {
-				path = path[1:]
-			}
-			count, err := api.PageCountFile(filepath.FromSlash(path))
-			if err != nil {
-				fyne.LogError("Failed to count pages for "+path, err)
-			}
-			t.pdfFiles = append(t.pdfFiles, pdfFileItem{Path: path, PageCount: count})
-			t.fileList.Refresh()
-		}, window)
+			ctx.RunSafely(func() {
+				path := reader.URI().Path()
+				// On Windows, file URIs from Fyne can have a leading slash.
+				// We remove it to ensure compatibility with file system operations.
+				if len(path) > 2 && path[0] == '/' && path[2] == ':' {
+					path = path[1:]
+				}
+				t.pdfFiles = append(t.pdfFiles, t.newFileItem(path))
+				t.fileList.Refresh()
+			})
+		}, ctx.Window)
 		fileDialog.SetFilter(storage.NewExtensionFileFilter([]string{".pdf"}))
 		fileDialog.Show()
-	})
+	}))
 
-	removeBtn := widget.NewButton("Remove", func() {
+	// Removing and reordering go through the undo stack (Ctrl+Z / Ctrl+Y).
+	t.removeBtn = widget.NewButton(i18n.T("Remove"), ctx.Safe(func() {
 		if selectedIndex < 0 || selectedIndex >= len(t.pdfFiles) {
 			return
 		}
-		t.pdfFiles = append(t.pdfFiles[:selectedIndex], t.pdfFiles[selectedIndex+1:]...)
-		selectedIndex = -1
-		t.fileList.UnselectAll()
-		t.fileList.Refresh()
-	})
-
-	moveUpBtn := widget.NewButton("Move Up", func() {
-		if selectedIndex <= 0 {
+		i, item := selectedIndex, t.pdfFiles[selectedIndex]
+		ctx.Undo.Do(undo.Command{
+			Title: i18n.T("Remove %s", filepath.Base(item.Path)),
+			Do:    func() { t.removeFile(i) },
+			Undo:  func() { t.insertFile(i, item) },
+		})
+	}))
+
+	t.moveUpBtn = widget.NewButton(i18n.T("Move Up"), ctx.Safe(func() {
+		if selectedIndex <= 0 || selectedIndex >= len(t.pdfFiles) {
 			return
 		}
-		t.pdfFiles[selectedIndex], t.pdfFiles[selectedIndex-1] = t.pdfFiles[selectedIndex-1], t.pdfFiles[selectedIndex]
-		t.fileList.Select(selectedIndex - 1)
-	})
+		t.moveFile(ctx, selectedIndex, selectedIndex-1)
+	}))
 
-	moveDownBtn := widget.NewButton("Move Down", func() {
+	t.moveDownBtn = widget.NewButton(i18n.T("Move Down"), ctx.Safe(func() {
 		if selectedIndex < 0 || selectedIndex >= len(t.pdfFiles)-1 {
 			return
 		}
-		t.pdfFiles[selectedIndex], t.pdfFiles[selectedIndex+1] = t.pdfFiles[selectedIndex+1], t.pdfFiles[selectedIndex]
-		t.fileList.Select(selectedIndex + 1)
-	})
+		t.moveFile(ctx, selectedIndex, selectedIndex+1)
+	}))
 
-	actionButtons := container.NewVBox(addBtn, removeBtn, moveUpBtn, moveDownBtn)
+	actionButtons := container.NewVBox(t.addBtn, t.removeBtn, t.moveUpBtn, t.moveDownBtn)
 
 	// --- Output & Merge (Bottom Panel) ---
 	outputEntry := widget.NewEntry()
 	outputEntry.Disable()
 
-	saveAsBtn := widget.NewButton("Save As...", func() {
+	t.saveAsBtn = widget.NewButton(i18n.T("Save As..."), ctx.Safe(func() {
 		fileDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
 			if err != nil || writer == nil {
 				return
 			}
-			path := writer.URI().Path()
-			if len(path) > 2 && path[0] == '/' && path[2] == ':' {
-				path = path[1:]
+			ctx.RunSafely(func() {
+				path := writer.URI().Path()
+				if len(path) > 2 && path[0] == '/' && path[2] == ':' {
+					path = path[1:]
+				}
+				outputEntry.SetText(path)
+			})
+		}, ctx.Window)
+		if dir := ctx.Settings.String(settingOutputDir, ""); dir != "" {
+			if location, err := storage.ListerForURI(storage.NewFileURI(dir)); err == nil {
+				fileDialog.SetLocation(location)
 			}
-			outputEntry.SetText(path)
-		}, window)
+		}
 		fileDialog.SetFileName("merged.pdf")
 		fileDialog.SetFilter(storage.NewExtensionFileFilter([]string{".pdf"}))
 		fileDialog.Show()
-	})
+	}))
 
-	mergeBtn := widget.NewButton("Merge PDFs", func() {
+	var mergeBtn *widget.Button
+	mergeBtn = widget.NewButton(i18n.T("Merge PDFs"), ctx.Safe(func() {
 		if len(t.pdfFiles) < 1 {
-			statusLabel.SetText("Error: Please add at least one PDF file.")
+			statusLabel.SetText(i18n.T("Error: Please add at least one PDF file."))
 			return
 		}
 		if outputEntry.Text == "" {
-			statusLabel.SetText("Error: Please select an output file location.")
+			statusLabel.SetText(i18n.T("Error: Please select an output file location."))
 			return
 		}
-		statusLabel.SetText("Merging...")
-		if err := mergePDFs(t.pdfFiles, outputEntry.Text); err != nil {
-			statusLabel.SetText("Error: " + err.Error())
-		} else {
-			statusLabel.SetText("Success! PDFs merged into " + filepath.Base(outputEntry.Text))
-		}
-	})
+		outFile := outputEntry.Text
+		statusLabel.SetText(i18n.T("Merging..."))
+		mergeBtn.Disable()
 
-	outputArea := container.NewBorder(nil, nil, nil, saveAsBtn, outputEntry)
+		t.submitMerge(ctx, outFile, func(err error) {
+			mergeBtn.Enable()
+			if err != nil {
+				statusLabel.SetText(i18n.T("Error: %s", err.Error()))
+			} else {
+				statusLabel.SetText(i18n.T("Success! PDFs merged into %s", filepath.Base(outFile)))
+			}
+		})
+	}))
+
+	t.mergeBtn = mergeBtn
+
+	outputArea := container.NewBorder(nil, nil, nil, t.saveAsBtn, outputEntry)
 	bottomPanel := container.NewVBox(outputArea, mergeBtn, statusLabel)
 
 	// --- Final Layout ---
@@ -232,8 +430,69 @@ func (t *PDFMergerTool) GetUI(window fyne.Window) fyne.CanvasObject {
 	return container.NewBorder(nil, bottomPanel, nil, nil, listContainer)
 }
 
+// --- System Tray ---
+
+// systrayItems adds a "Recent merged PDFs" submenu to the system tray.
+func systrayItems(ctx *tools.ToolContext) []*fyne.MenuItem {
+	recent := recentFiles(ctx.Settings)
+	if len(recent) == 0 {
+		return nil
+	}
+
+	submenu := fyne.NewMenu("")
+	for _, path := range recent {
+		file := path
+		submenu.Items = append(submenu.Items, fyne.NewMenuItem(filepath.Base(file), func() {
+			u, err := url.Parse(storage.NewFileURI(file).String())
+			if err == nil {
+				err = fyne.CurrentApp().OpenURL(u)
+			}
+			if err != nil {
+				ctx.Logger.Error("failed to open merged file", "path", file, "err", err)
+			}
+		}))
+	}
+	submenu.Items = append(submenu.Items, fyne.NewMenuItemSeparator(), fyne.NewMenuItem(i18n.T("Clear list"), func() {
+		ctx.Settings.SetString(settingRecent, "")
+		ctx.Systray.Refresh()
+	}))
+
+	item := fyne.NewMenuItem(i18n.T("Recent merged PDFs"), nil)
+	item.ChildMenu = submenu
+	return []*fyne.MenuItem{item}
+}
+
+// recentFiles returns the last merged files that still exist, newest first.
+func recentFiles(s tools.Settings) []string {
+	var result []string
+	for _, path := range strings.Split(s.String(settingRecent, ""), "\n") {
+		if path == "" {
+			continue
+		}
+		if _, err := os.Stat(path); err == nil {
+			result = append(result, path)
+		}
+	}
+	return result
+}
+
+// addRecent puts path at the top of the recent files and refreshes the tray.
+func addRecent(ctx *tools.ToolContext, path string) {
+	recent := []string{path}
+	for _, p := range recentFiles(ctx.Settings) {
+		if p != path && len(recent) < maxRecent {
+			recent = append(recent, p)
+		}
+	}
+	ctx.Settings.SetString(settingRecent, strings.Join(recent, "\n"))
+	ctx.Systray.Refresh()
+}
+
 // --- Backend Logic ---
-func mergePDFs(files []pdfFileItem, outFile string) error {
+
+// mergePDFs merges files into outFile, extracting the selected pages first. It
+// stops between files when ctx is canceled.
+func mergePDFs(ctx context.Context, files []pdfFileItem, outFile string, r jobs.Reporter) error {
 	if len(files) == 0 {
 		return errors.New("no files to merge")
 	}
@@ -247,7 +506,13 @@ func mergePDFs(files []pdfFileItem, outFile string) error {
 		}
 	}()
 
-	for _, f := range files {
+	for i, f := range files {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		r.SetProgress(float64(i) / float64(len(files)+1))
+		r.Logf(i18n.T("Adding %s"), filepath.Base(f.Path))
+
 		pageRange := strings.TrimSpace(f.PageRange)
 
 		if pageRange != "" {
@@ -295,6 +560,11 @@ func mergePDFs(files []pdfFileItem, outFile string) error {
 		}
 	}
 
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.SetProgress(float64(len(files)) / float64(len(files)+1))
+	r.Logf(i18n.T("Writing %s"), outFile)
 	if err := api.MergeCreateFile(filePaths, outFile, false, nil); err != nil {
 		return fmt.Errorf("failed to merge pdfs: %w", err)
 	}
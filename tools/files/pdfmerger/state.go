@@ -0,0 +1,55 @@
+package pdfmerger
+
+import (
+	"encoding/json"
+	"log/slog"
+	"os"
+
+	"github.com/Lec7ral/MultiTool/logging"
+)
+
+// --- Workspace State ---
+
+// savedFile is a file of the merge list as stored in a workspace. Page counts
+// are not stored: they are read again in case the file changed.
+type savedFile struct {
+	Path      string `json:"path"`
+	PageRange string `json:"pageRange,omitempty"`
+}
+
+// SaveState stores the merge list, in order, with each file's page selection.
+func (t *PDFMergerTool) SaveState() (json.RawMessage, error) {
+	files := make([]savedFile, len(t.pdfFiles))
+	for i, f := range t.pdfFiles {
+		files[i] = savedFile{Path: f.Path, PageRange: f.PageRange}
+	}
+	return json.Marshal(files)
+}
+
+// RestoreState replaces the merge list with a saved one. Files that no longer
+// exist are left out.
+func (t *PDFMergerTool) RestoreState(state json.RawMessage) error {
+	var files []savedFile
+	if err := json.Unmarshal(state, &files); err != nil {
+		return err
+	}
+	t.pdfFiles = make([]pdfFileItem, 0, len(files))
+	for _, f := range files {
+		if _, err := os.Stat(f.Path); err != nil {
+			slog.Warn("dropping missing file from the merge list", logging.ToolKey, descriptor.Name, "path", f.Path)
+			continue
+		}
+		item := t.newFileItem(f.Path)
+		item.PageRange = f.PageRange
+		t.pdfFiles = append(t.pdfFiles, item)
+	}
+	if t.fileList != nil {
+		t.fileList.UnselectAll()
+		t.fileList.Refresh()
+	}
+	// Earlier edits refer to the list that was just replaced.
+	if t.ctx != nil {
+		t.ctx.Undo.Clear()
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package pdfmerger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/Lec7ral/MultiTool/jobs"
+	"github.com/Lec7ral/MultiTool/tools"
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+)
+
+// --- Workflow Operations ---
+
+var operations = []tools.Operation{
+	{
+		Name:   "merge",
+		Title:  "Merge PDFs",
+		Input:  tools.Files,
+		Output: tools.Files,
+		Params: []tools.Param{
+			{Key: "output", Label: "Output file", Required: true,
+				Description: "Path of the merged PDF. Inputs can select pages as in the command line (a.pdf:1-3)."},
+		},
+		Run: runMergeOperation,
+	},
+	{
+		Name:   "count",
+		Title:  "Count pages",
+		Input:  tools.Files,
+		Output: tools.Text,
+		Run:    runCountOperation,
+	},
+}
+
+// runMergeOperation merges the input files and passes on the merged file.
+func runMergeOperation(ctx context.Context, in tools.Data, params map[string]string, r jobs.Reporter) (tools.Data, error) {
+	out := params["output"]
+	if out == "" {
+		return tools.Data{}, errors.New("no output file")
+	}
+	files := make([]pdfFileItem, 0, len(in.Files))
+	for _, f := range in.Files {
+		files = append(files, parseFileArg(f))
+	}
+	if err := mergePDFs(ctx, files, out, r); err != nil {
+		return tools.Data{}, err
+	}
+	return tools.FilesData(out), nil
+}
+
+// runCountOperation reports the page count of each input file, one per line.
+func runCountOperation(ctx context.Context, in tools.Data, _ map[string]string, r jobs.Reporter) (tools.Data, error) {
+	var b strings.Builder
+	for i, f := range in.Files {
+		if err := ctx.Err(); err != nil {
+			return tools.Data{}, err
+		}
+		r.SetProgress(float64(i) / float64(len(in.Files)))
+		count, err := api.PageCountFile(filepath.FromSlash(f))
+		if err != nil {
+			return tools.Data{}, fmt.Errorf("count pages of %s: %w", filepath.Base(f), err)
+		}
+		fmt.Fprintf(&b, "%s\t%d\n", f, count)
+	}
+	return tools.TextData(strings.TrimSuffix(b.String(), "\n")), nil
+}
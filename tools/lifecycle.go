@@ -0,0 +1,45 @@
+package tools
+
+// Ganchos opcionales del ciclo de vida de una herramienta. Una herramienta solo
+// implementa los que necesita.
+
+// Initializer se llama una sola vez, justo después de construir la herramienta.
+type Initializer interface {
+	Init() error
+}
+
+// Activator se llama cuando la herramienta pasa a ser la visible.
+type Activator interface {
+	Activate()
+}
+
+// Deactivator se llama cuando la herramienta deja de ser la visible (cambio de
+// pestaña o cierre de la ventana).
+type Deactivator interface {
+	Deactivate()
+}
+
+// Disposer se llama antes de descartar la instancia, para liberar recursos.
+type Disposer interface {
+	Dispose()
+}
+
+// Evictable permite a una herramienta impedir que se descargue por inactividad,
+// por ejemplo mientras guarda trabajo del usuario que se perdería.
+type Evictable interface {
+	CanEvict() bool
+}
+
+// Activate llama al gancho Activate de t, si lo implementa.
+func Activate(t Tool) {
+	if a, ok := t.(Activator); ok {
+		a.Activate()
+	}
+}
+
+// Deactivate llama al gancho Deactivate de t, si lo implementa.
+func Deactivate(t Tool) {
+	if d, ok := t.(Deactivator); ok {
+		d.Deactivate()
+	}
+}
@@ -0,0 +1,55 @@
+package networkswitcher
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Lec7ral/MultiTool/i18n"
+	"github.com/Lec7ral/MultiTool/jobs"
+	"github.com/Lec7ral/MultiTool/tools"
+	"github.com/Lec7ral/MultiTool/tools/profiles"
+)
+
+// --- Automation API ---
+
+var endpoints = []tools.Endpoint{
+	{
+		Method:  http.MethodGet,
+		Path:    "/v1/net/profiles",
+		Summary: "List the saved network profiles",
+		Handle:  handleListProfiles,
+	},
+	{
+		Method:  http.MethodPost,
+		Path:    "/v1/net/profiles/{name}/apply",
+		Summary: "Apply a network profile by name",
+		Handle:  handleApplyProfile,
+	},
+}
+
+func handleListProfiles(_ *tools.ToolContext, r *http.Request) (any, error) {
+	loaded, err := profiles.LoadProfiles()
+	if err != nil {
+		return nil, err
+	}
+	if loaded == nil {
+		loaded = []profiles.Profile{} // An empty JSON list rather than null.
+	}
+	return loaded, nil
+}
+
+// handleApplyProfile applies the profile as a background job, so it shows up
+// in the jobs panel, and answers when the job is done.
+func handleApplyProfile(ctx *tools.ToolContext, r *http.Request) (any, error) {
+	p, err := FindProfile(r.PathValue("name"))
+	if err != nil {
+		return nil, err
+	}
+	job := ctx.Submit(i18n.T("Apply profile %s", p.Name), func(jobCtx context.Context, r jobs.Reporter) error {
+		return ApplyProfile(jobCtx, p, r)
+	})
+	if err := job.Wait(); err != nil {
+		return nil, err
+	}
+	return applyResult{Profile: p.Name}, nil
+}
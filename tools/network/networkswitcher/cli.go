@@ -0,0 +1,79 @@
+package networkswitcher
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Lec7ral/MultiTool/i18n"
+	"github.com/Lec7ral/MultiTool/jobs"
+	"github.com/Lec7ral/MultiTool/tools"
+	"github.com/Lec7ral/MultiTool/tools/profiles"
+)
+
+// --- Command Line ---
+
+var commands = []tools.Command{
+	{
+		Group:   "net",
+		Name:    "list",
+		Summary: "List the saved network profiles",
+		Run:     runListCommand,
+	},
+	{
+		Group:   "net",
+		Name:    "apply",
+		Usage:   "PROFILE",
+		Summary: "Apply a network profile by name",
+		Run:     runApplyCommand,
+	},
+}
+
+// profileList is the output of "net list".
+type profileList []profiles.Profile
+
+func (l profileList) String() string {
+	var b strings.Builder
+	for _, p := range l {
+		proxy := i18n.T("no proxy")
+		if p.ProxyEnabled {
+			proxy = i18n.T("proxy %s", p.ProxyServer)
+		}
+		fmt.Fprintf(&b, "%s\t%s\t%s\n", p.Name, p.NetworkPriority, proxy)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// applyResult is the output of "net apply".
+type applyResult struct {
+	Profile string `json:"profile"`
+}
+
+func (r applyResult) String() string {
+	return i18n.T("Profile '%s' applied.", r.Profile)
+}
+
+func runListCommand(ctx context.Context, args []string) (any, error) {
+	if len(args) != 0 {
+		return nil, fmt.Errorf("%w: net list takes no arguments", tools.ErrUsage)
+	}
+	loaded, err := profiles.LoadProfiles()
+	if err != nil {
+		return nil, err
+	}
+	return profileList(loaded), nil
+}
+
+func runApplyCommand(ctx context.Context, args []string) (any, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("%w: expected exactly one profile name", tools.ErrUsage)
+	}
+	p, err := FindProfile(args[0])
+	if err != nil {
+		return nil, err
+	}
+	if err := ApplyProfile(ctx, p, jobs.Discard); err != nil {
+		return nil, err
+	}
+	return applyResult{Profile: p.Name}, nil
+}
@@ -1,59 +1,223 @@
 package networkswitcher
 
 import (
+	"context"
+	"embed"
 	"fmt"
+	"log/slog"
 	"os/exec"
+	"slices"
+	"strings"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
+	"github.com/Lec7ral/MultiTool/assets"
+	"github.com/Lec7ral/MultiTool/i18n"
+	"github.com/Lec7ral/MultiTool/jobs"
+	"github.com/Lec7ral/MultiTool/logging"
+	"github.com/Lec7ral/MultiTool/tools"
 	"github.com/Lec7ral/MultiTool/tools/profiles"
+	"github.com/Lec7ral/MultiTool/undo"
 )
 
-// --- Callback para actualizar la bandeja del sistema ---
-var systrayCallback func()
+// --- Registration ---
+
+// toolName is the name the tool registers with.
+const toolName = "Network Switcher"
+
+var descriptor = tools.ToolDescriptor{
+	Name:        toolName,
+	Description: "Manage and apply network configuration profiles",
+	Category:    "Network",
+	Icon:        assets.Icon(assets.IconNetwork),
+	Constructor: func() tools.Tool { return New() },
+	Commands:    commands,
+	Operations:  operations,
+	Endpoints:   endpoints,
+	Systray:     systrayItems,
+	Shortcuts:   shortcuts,
+	Actions:     actions,
+}
+
+//go:embed locales
+var locales embed.FS
 
-func SetSystrayCallback(callback func()) {
-	systrayCallback = callback
+func init() {
+	if err := i18n.AddCatalogFS(locales, "locales"); err != nil {
+		slog.Error("failed to load network switcher translations", "err", err)
+	}
+	tools.Register(descriptor)
 }
 
 // --- Tool Definition ---
-type NetworkSwitcherTool struct{}
+type NetworkSwitcherTool struct {
+	// Set by GetUI.
+	profileSelect *widget.Select
+	applyBtn      *widget.Button
+	manageBtn     *widget.Button
+	apply         func(p profiles.Profile)
+
+	// manager is the profile manager window while it is open. There is only
+	// one, because its edits go to the tool's single undo history.
+	// closeManager saves its list and closes it.
+	manager      fyne.Window
+	closeManager func()
+}
 
 func New() *NetworkSwitcherTool {
 	return &NetworkSwitcherTool{}
 }
 
 func (t *NetworkSwitcherTool) GetName() string {
-	return "Network Switcher"
+	return descriptor.Name
 }
 
 func (t *NetworkSwitcherTool) GetDescription() string {
-	return "Manage and apply network configuration profiles"
+	return descriptor.Description
 }
 
 func (t *NetworkSwitcherTool) GetCategory() string {
-	return "Network"
+	return descriptor.Category
 }
 
 func (t *NetworkSwitcherTool) GetIcon() fyne.Resource {
-	resource, err := fyne.LoadResourceFromPath("assets/change.svg")
+	return descriptor.Icon
+}
+
+// CanEvict keeps the tool loaded while the profile manager is open, since its
+// edits are only saved when the window closes.
+func (t *NetworkSwitcherTool) CanEvict() bool {
+	return t.manager == nil
+}
+
+// Dispose saves and closes the profile manager, if it is open, and drops the
+// references to the UI built by GetUI.
+func (t *NetworkSwitcherTool) Dispose() {
+	if t.closeManager != nil {
+		t.closeManager()
+	}
+	t.profileSelect = nil
+	t.applyBtn = nil
+	t.manageBtn = nil
+	t.apply = nil
+}
+
+// applyActionPrefix precedes the profile name in the ID of its "Apply profile" action.
+const applyActionPrefix = "apply:"
+
+// actions offers one "Apply profile" entry per saved profile in the command palette.
+func actions() []tools.Action {
+	loadedProfiles, err := profiles.LoadProfiles()
 	if err != nil {
-		fyne.LogError("Failed to load custom icon", err)
 		return nil
 	}
-	return resource
+	list := make([]tools.Action, 0, len(loadedProfiles)+1)
+	for _, p := range loadedProfiles {
+		list = append(list, tools.Action{
+			ID:          applyActionPrefix + p.Name,
+			Title:       i18n.T("Apply profile %s", p.Name),
+			Description: profileSummary(p),
+		})
+	}
+	return append(list, tools.Action{ID: "manage", Title: i18n.T("Manage Profiles")})
+}
+
+// RunAction applies the profile named in id or opens the profile manager.
+func (t *NetworkSwitcherTool) RunAction(id string) {
+	if id == "manage" {
+		t.RunShortcut("manage")
+		return
+	}
+	name, ok := strings.CutPrefix(id, applyActionPrefix)
+	if !ok || t.apply == nil {
+		return
+	}
+	loadedProfiles, err := profiles.LoadProfiles()
+	if err != nil {
+		return
+	}
+	for _, p := range loadedProfiles {
+		if p.Name == name {
+			t.profileSelect.SetSelected(p.Name)
+			t.apply(p)
+			return
+		}
+	}
+}
+
+// shortcuts apply the selected profile with the primary action shortcut and
+// open the profile manager.
+var shortcuts = []tools.Shortcut{
+	{ID: tools.ShortcutPrimary, Title: "Apply Profile"},
+	{ID: "manage", Title: "Manage Profiles", Keys: "Ctrl+M"},
+}
+
+// RunShortcut presses the button behind one of the shortcuts.
+func (t *NetworkSwitcherTool) RunShortcut(id string) {
+	var b *widget.Button
+	switch id {
+	case tools.ShortcutPrimary:
+		b = t.applyBtn
+	case "manage":
+		b = t.manageBtn
+	}
+	if b != nil && !b.Disabled() {
+		b.OnTapped()
+	}
+}
+
+// systrayItems adds a "Mode" submenu to the system tray with one entry per
+// saved profile.
+func systrayItems(ctx *tools.ToolContext) []*fyne.MenuItem {
+	loadedProfiles, err := profiles.LoadProfiles()
+	if err != nil || len(loadedProfiles) == 0 {
+		return nil
+	}
+
+	profileSubMenu := fyne.NewMenu("")
+	for _, p := range loadedProfiles {
+		profile := p
+		item := fyne.NewMenuItem(profile.Name, func() {
+			ctx.Submit(i18n.T("Apply profile %s", profile.Name), func(jobCtx context.Context, r jobs.Reporter) error {
+				err := ApplyProfile(jobCtx, profile, r)
+				if err != nil {
+					ctx.Logger.Error("failed to apply profile", "profile", profile.Name, "err", err)
+					ctx.Notifier.Notify("Toolbox", i18n.T("Failed to apply profile %s", profile.Name))
+				} else {
+					ctx.Notifier.Notify("Toolbox", i18n.T("Profile '%s' applied.", profile.Name))
+				}
+				return err
+			})
+		})
+		profileSubMenu.Items = append(profileSubMenu.Items, item)
+	}
+
+	modeMenuItem := fyne.NewMenuItem(i18n.T("Mode"), nil)
+	modeMenuItem.ChildMenu = profileSubMenu
+	return []*fyne.MenuItem{modeMenuItem}
+}
+
+// profileSummary describes the network priority and proxy of a profile.
+func profileSummary(p profiles.Profile) string {
+	if p.ProxyEnabled {
+		return p.NetworkPriority + ", " + i18n.T("proxy %s", p.ProxyServer)
+	}
+	return p.NetworkPriority + ", " + i18n.T("no proxy")
 }
 
 // --- Main UI ---
-func (t *NetworkSwitcherTool) GetUI(window fyne.Window) fyne.CanvasObject {
+func (t *NetworkSwitcherTool) GetUI(ctx *tools.ToolContext) fyne.CanvasObject {
 	statusLabel := widget.NewLabel("")
 	statusLabel.Wrapping = fyne.TextWrapWord
 
 	// --- Profile Selection ---
 	loadedProfiles, err := profiles.LoadProfiles()
 	if err != nil {
-		return widget.NewLabel("Error loading profiles: " + err.Error())
+		return widget.NewLabel(i18n.T("Error loading profiles: %s", err.Error()))
 	}
 
 	var selectedProfile profiles.Profile
@@ -75,7 +239,10 @@ func (t *NetworkSwitcherTool) GetUI(window fyne.Window) fyne.CanvasObject {
 	})
 
 	refreshAll := func() {
-		loadedProfiles, _ = profiles.LoadProfiles()
+		var err error
+		if loadedProfiles, err = profiles.LoadProfiles(); err != nil {
+			ctx.Logger.Error("failed to reload profiles", "err", err)
+		}
 		profileNames := func() []string {
 			names := make([]string, len(loadedProfiles))
 			for i, p := range loadedProfiles {
@@ -86,9 +253,8 @@ func (t *NetworkSwitcherTool) GetUI(window fyne.Window) fyne.CanvasObject {
 		profileSelect.Options = profileNames
 		profileSelect.Refresh()
 
-		if systrayCallback != nil {
-			systrayCallback()
-		}
+		// The tray lists the profiles too.
+		ctx.Systray.Refresh()
 	}
 
 	if len(loadedProfiles) > 0 {
@@ -97,58 +263,123 @@ func (t *NetworkSwitcherTool) GetUI(window fyne.Window) fyne.CanvasObject {
 	}
 
 	// --- Main Buttons ---
-	applyBtn := widget.NewButton("Apply Profile", func() {
+	var applyBtn *widget.Button
+	t.apply = func(profile profiles.Profile) {
+		statusLabel.SetText(i18n.T("Applying profile '%s'...", profile.Name))
+		applyBtn.Disable()
+
+		// Applying runs netsh and reg, which can take a while: do it as a background job.
+		ctx.Submit(i18n.T("Apply profile %s", profile.Name), func(jobCtx context.Context, r jobs.Reporter) error {
+			err := ApplyProfile(jobCtx, profile, r)
+			fyne.Do(ctx.Safe(func() {
+				applyBtn.Enable()
+				if err != nil {
+					ctx.Logger.Error("failed to apply profile", "profile", profile.Name, "err", err)
+					statusLabel.SetText(i18n.T("Failed to apply profile: %s", err.Error()))
+				} else {
+					statusLabel.SetText(i18n.T("Profile '%s' applied successfully.", profile.Name))
+					ctx.Status.SetStatus(i18n.T("Network profile '%s' active", profile.Name))
+				}
+			}))
+			return err
+		})
+	}
+	// Callbacks go through ctx.Safe so a panic only takes down this tool.
+	applyBtn = widget.NewButton(i18n.T("Apply Profile"), ctx.Safe(func() {
 		if selectedProfile.Name == "" {
-			statusLabel.SetText("No profile selected.")
+			statusLabel.SetText(i18n.T("No profile selected."))
 			return
 		}
-		statusLabel.SetText(fmt.Sprintf("Applying profile '%s'...", selectedProfile.Name))
-		if err := ApplyProfile(selectedProfile); err != nil {
-			statusLabel.SetText(fmt.Sprintf("Failed to apply profile: %s", err.Error()))
-		} else {
-			statusLabel.SetText(fmt.Sprintf("Profile '%s' applied successfully.", selectedProfile.Name))
-		}
-	})
+		t.apply(selectedProfile)
+	}))
 
-	manageBtn := widget.NewButton("Manage Profiles", func() {
-		newManagerWindow(window, refreshAll).Show()
-	})
+	t.manageBtn = widget.NewButton(i18n.T("Manage Profiles"), ctx.Safe(func() {
+		if t.manager != nil {
+			t.manager.Show()
+			t.manager.RequestFocus()
+			return
+		}
+		t.manager, t.closeManager = newManagerWindow(ctx, ctx.Safe(func() {
+			t.manager, t.closeManager = nil, nil
+			refreshAll()
+		}))
+		t.manager.Show()
+	}))
+	t.profileSelect = profileSelect
+	t.applyBtn = applyBtn
 
 	return container.NewVBox(
-		widget.NewLabelWithStyle("Select a Profile", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		widget.NewLabelWithStyle(i18n.T("Select a Profile"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
 		profileSelect,
 		applyBtn,
 		widget.NewSeparator(),
-		manageBtn,
+		t.manageBtn,
 		widget.NewSeparator(),
-		widget.NewLabelWithStyle("Status", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		widget.NewLabelWithStyle(i18n.T("Status"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
 		statusLabel,
 		widget.NewSeparator(),
-		widget.NewLabel("NOTE: This tool requires the application to be run with Administrator privileges."),
+		widget.NewLabel(i18n.T("NOTE: This tool requires the application to be run with Administrator privileges.")),
 	)
 }
 
 // --- Profile Manager Window ---
-func newManagerWindow(parent fyne.Window, onClosed func()) fyne.Window {
+
+// newManagerWindow builds the profile manager. Besides the window it returns a
+// function that saves the list and closes it, for when the tool is disposed.
+func newManagerWindow(ctx *tools.ToolContext, onClosed func()) (fyne.Window, func()) {
 	app := fyne.CurrentApp()
-	w := app.NewWindow("Profile Manager")
+	w := app.NewWindow(i18n.T("Profile Manager"))
 	w.Resize(fyne.NewSize(600, 400))
 	w.CenterOnScreen()
 
 	// --- Data & Form Widgets ---
-	loadedProfiles, _ := profiles.LoadProfiles()
-	var selectedProfile *profiles.Profile
+	loadedProfiles, err := profiles.LoadProfiles()
+	if err != nil {
+		ctx.Logger.Error("failed to load profiles", "err", err)
+	}
+	selected := -1 // Index of the profile in the form, or -1 for a new one.
+
+	// Edits stay in memory, where they can be undone, and profiles.json is only
+	// written when the window closes or the app quits, so the CLI, the tray and
+	// the automation API never see changes the user may still take back.
+	saved := slices.Clone(loadedProfiles)
+	save := func() error {
+		if slices.Equal(saved, loadedProfiles) {
+			return nil
+		}
+		if err := profiles.SaveProfiles(loadedProfiles); err != nil {
+			ctx.Logger.Error("failed to save profiles", "err", err)
+			return err
+		}
+		ctx.Logger.Info("profiles saved", "count", len(loadedProfiles))
+		saved = slices.Clone(loadedProfiles)
+		return nil
+	}
+	// Quitting from the tray doesn't go through the close intercept.
+	removeExitHook := ctx.Exit.OnExit(func() { save() })
+	w.SetCloseIntercept(func() {
+		if err := save(); err != nil {
+			dialog.ShowConfirm(i18n.T("Profiles not saved"), i18n.T("%s\n\nClose and discard the changes?", err.Error()),
+				func(discard bool) {
+					if discard {
+						w.Close()
+					}
+				}, w)
+			return
+		}
+		w.Close()
+	})
 
 	nameEntry := widget.NewEntry()
 	prioritySelect := widget.NewSelect([]string{"Ethernet", "Wi-Fi"}, nil)
-	proxyEnabledCheck := widget.NewCheck("Proxy Enabled", nil)
+	proxyEnabledCheck := widget.NewCheck(i18n.T("Proxy Enabled"), nil)
 	proxyServerEntry := widget.NewEntry()
 
 	form := widget.NewForm(
-		widget.NewFormItem("Name", nameEntry),
-		widget.NewFormItem("Priority", prioritySelect),
+		widget.NewFormItem(i18n.T("Name"), nameEntry),
+		widget.NewFormItem(i18n.T("Priority"), prioritySelect),
 		widget.NewFormItem("", proxyEnabledCheck),
-		widget.NewFormItem("Proxy Server", proxyServerEntry),
+		widget.NewFormItem(i18n.T("Proxy Server"), proxyServerEntry),
 	)
 
 	// --- Profile List ---
@@ -159,118 +390,190 @@ func newManagerWindow(parent fyne.Window, onClosed func()) fyne.Window {
 	)
 
 	profileList.OnSelected = func(id widget.ListItemID) {
-		selectedProfile = &loadedProfiles[id]
-		nameEntry.SetText(selectedProfile.Name)
-		prioritySelect.SetSelected(selectedProfile.NetworkPriority)
-		proxyEnabledCheck.SetChecked(selectedProfile.ProxyEnabled)
-		proxyServerEntry.SetText(selectedProfile.ProxyServer)
+		ctx.RunSafely(func() {
+			selected = id
+			p := loadedProfiles[id]
+			nameEntry.SetText(p.Name)
+			prioritySelect.SetSelected(p.NetworkPriority)
+			proxyEnabledCheck.SetChecked(p.ProxyEnabled)
+			proxyServerEntry.SetText(p.ProxyServer)
+		})
 	}
 
 	// --- Toolbar Buttons ---
-	newBtn := widget.NewButton("New", func() {
-		selectedProfile = nil
+	// Like in the main UI, callbacks go through ctx.Safe.
+	newBtn := widget.NewButton(i18n.T("New"), ctx.Safe(func() {
+		selected = -1
 		profileList.UnselectAll()
 		nameEntry.SetText("")
 		prioritySelect.ClearSelected()
 		proxyEnabledCheck.SetChecked(false)
 		proxyServerEntry.SetText("")
-	})
+	}))
+
+	// Edits go to the tool's history. Every edit replaces the whole list, so
+	// undoing one just puts the previous list back.
+	history := ctx.Undo
+	setProfiles := func(list []profiles.Profile, show int) {
+		loadedProfiles = slices.Clone(list)
+		profileList.UnselectAll()
+		profileList.Refresh()
+		if show >= 0 && show < len(loadedProfiles) {
+			profileList.Select(show) // Fills the form from the restored list.
+		} else {
+			newBtn.OnTapped()
+		}
+	}
+	change := func(title string, edit func([]profiles.Profile) ([]profiles.Profile, int)) {
+		before, shown := slices.Clone(loadedProfiles), selected
+		after, show := edit(slices.Clone(loadedProfiles))
+		history.Do(undo.Command{
+			Title: title,
+			Do:    func() { setProfiles(after, show) },
+			Undo:  func() { setProfiles(before, shown) },
+		})
+	}
 
-	deleteBtn := widget.NewButton("Delete", func() {
-		if selectedProfile == nil {
+	deleteBtn := widget.NewButton(i18n.T("Delete"), ctx.Safe(func() {
+		if selected < 0 {
 			return
 		}
-		var newProfiles []profiles.Profile
-		for _, p := range loadedProfiles {
-			if p.Name != selectedProfile.Name {
-				newProfiles = append(newProfiles, p)
-			}
+		i := selected
+		change(i18n.T("Delete %s", loadedProfiles[i].Name), func(list []profiles.Profile) ([]profiles.Profile, int) {
+			return slices.Delete(list, i, i+1), -1
+		})
+	}))
+
+	// Update puts the form into the list; the list is saved when the window closes.
+	updateBtn := widget.NewButton(i18n.T("Update"), ctx.Safe(func() {
+		edited := profiles.Profile{
+			Name:            nameEntry.Text,
+			NetworkPriority: prioritySelect.Selected,
+			ProxyEnabled:    proxyEnabledCheck.Checked,
+			ProxyServer:     proxyServerEntry.Text,
 		}
-		loadedProfiles = newProfiles
-		profiles.SaveProfiles(loadedProfiles)
-		profileList.Refresh()
-		newBtn.OnTapped()
-	})
-
-	saveBtn := widget.NewButton("Save", func() {
-		if selectedProfile != nil { // Update existing
-			selectedProfile.Name = nameEntry.Text
-			selectedProfile.NetworkPriority = prioritySelect.Selected
-			selectedProfile.ProxyEnabled = proxyEnabledCheck.Checked
-			selectedProfile.ProxyServer = proxyServerEntry.Text
-		} else { // Create new
-			newProfile := profiles.Profile{
-				Name:            nameEntry.Text,
-				NetworkPriority: prioritySelect.Selected,
-				ProxyEnabled:    proxyEnabledCheck.Checked,
-				ProxyServer:     proxyServerEntry.Text,
+		i := selected
+		change(i18n.T("Edit %s", edited.Name), func(list []profiles.Profile) ([]profiles.Profile, int) {
+			if i >= 0 { // Update existing
+				list[i] = edited
+				return list, i
 			}
-			loadedProfiles = append(loadedProfiles, newProfile)
+			return append(list, edited), len(list) // Create new
+		})
+	}))
+
+	// --- Undo / Redo (Ctrl+Z / Ctrl+Y) ---
+	undoBtn := widget.NewButtonWithIcon(i18n.T("Undo"), theme.ContentUndoIcon(), ctx.Safe(func() { history.Undo() }))
+	redoBtn := widget.NewButtonWithIcon(i18n.T("Redo"), theme.ContentRedoIcon(), ctx.Safe(func() { history.Redo() }))
+	updateHistoryButtons := func() {
+		if history.CanUndo() {
+			undoBtn.Enable()
+		} else {
+			undoBtn.Disable()
 		}
-		profiles.SaveProfiles(loadedProfiles)
-		profileList.Refresh()
-	})
+		if history.CanRedo() {
+			redoBtn.Enable()
+		} else {
+			redoBtn.Disable()
+		}
+	}
+	removeHistoryListener := history.OnChange(updateHistoryButtons)
+	updateHistoryButtons()
+	w.Canvas().AddShortcut(&fyne.ShortcutUndo{}, func(fyne.Shortcut) { undoBtn.OnTapped() })
+	w.Canvas().AddShortcut(&fyne.ShortcutRedo{}, func(fyne.Shortcut) { redoBtn.OnTapped() })
 
-	toolbar := container.NewHBox(newBtn, saveBtn, deleteBtn)
+	toolbar := container.NewHBox(newBtn, updateBtn, deleteBtn, layout.NewSpacer(), undoBtn, redoBtn)
 	split := container.NewHSplit(profileList, container.NewVBox(form, toolbar))
 	split.Offset = 0.3
 
 	w.SetContent(split)
-	w.SetOnClosed(onClosed) // Refresh the main UI when this window closes
-	return w
+	w.SetOnClosed(func() {
+		removeExitHook()
+		removeHistoryListener()
+		history.Clear() // Its commands edit this window's list.
+		onClosed()      // Refresh the main UI when this window closes
+	})
+	return w, func() {
+		save() // Already logged if it fails; the window is going away anyway.
+		w.Close()
+	}
 }
 
 // --- Backend Logic ---
 
-// ApplyProfile applies all settings from a given profile.
-func ApplyProfile(p profiles.Profile) error {
+// ApplyProfile applies all settings from a given profile. The commands it runs
+// are killed when ctx is canceled.
+func ApplyProfile(ctx context.Context, p profiles.Profile, r jobs.Reporter) error {
+	r.Logf(i18n.T("Applying profile '%s'"), p.Name)
+	r.SetProgress(0)
 	if p.NetworkPriority == "Ethernet" {
-		if err := SetInterfaceMetric("Ethernet", 10); err != nil {
+		if err := SetInterfaceMetric(ctx, "Ethernet", 10); err != nil {
 			return err
 		}
-		if err := SetInterfaceMetric("Wi-Fi", 20); err != nil {
+		r.SetProgress(0.33)
+		if err := SetInterfaceMetric(ctx, "Wi-Fi", 20); err != nil {
 			return err
 		}
 	} else if p.NetworkPriority == "Wi-Fi" {
-		if err := SetInterfaceMetric("Wi-Fi", 10); err != nil {
+		if err := SetInterfaceMetric(ctx, "Wi-Fi", 10); err != nil {
 			return err
 		}
-		if err := SetInterfaceMetric("Ethernet", 20); err != nil {
+		r.SetProgress(0.33)
+		if err := SetInterfaceMetric(ctx, "Ethernet", 20); err != nil {
 			return err
 		}
 	}
+	r.SetProgress(0.66)
+	r.Logf(i18n.T("Network priority set to %s"), p.NetworkPriority)
 
-	return SetProxyState(p.ProxyEnabled, p.ProxyServer)
+	if err := SetProxyState(ctx, p.ProxyEnabled, p.ProxyServer); err != nil {
+		return err
+	}
+	r.Logf(i18n.T("Proxy enabled: %t"), p.ProxyEnabled)
+	return nil
 }
 
-// SetInterfaceMetric sets the metric for a network interface.
-func SetInterfaceMetric(name string, metric int) error {
-	cmd := exec.Command("netsh", "interface", "ipv4", "set", "interface", fmt.Sprintf("interface=%s", name), fmt.Sprintf("metric=%d", metric))
-	output, err := cmd.CombinedOutput()
+// FindProfile loads the saved profiles and returns the one called name. The
+// error wraps tools.ErrNotFound if there is none.
+func FindProfile(name string) (profiles.Profile, error) {
+	loaded, err := profiles.LoadProfiles()
 	if err != nil {
-		return fmt.Errorf("%s: %s", err, string(output))
+		return profiles.Profile{}, err
 	}
-	return nil
+	for _, p := range loaded {
+		if p.Name == name {
+			return p, nil
+		}
+	}
+	return profiles.Profile{}, fmt.Errorf("profile %q %w", name, tools.ErrNotFound)
+}
+
+// SetInterfaceMetric sets the metric for a network interface.
+func SetInterfaceMetric(ctx context.Context, name string, metric int) error {
+	return runCommand(ctx, "netsh", "interface", "ipv4", "set", "interface", fmt.Sprintf("interface=%s", name), fmt.Sprintf("metric=%d", metric))
 }
 
 // SetProxyState enables or disables the system proxy.
-func SetProxyState(enable bool, server string) error {
+func SetProxyState(ctx context.Context, enable bool, server string) error {
 	regPath := "HKCU\\Software\\Microsoft\\Windows\\CurrentVersion\\Internet Settings"
 	if enable {
-		cmdEnable := exec.Command("reg", "add", regPath, "/v", "ProxyEnable", "/t", "REG_DWORD", "/d", "1", "/f")
-		if _, err := cmdEnable.CombinedOutput(); err != nil {
+		if err := runCommand(ctx, "reg", "add", regPath, "/v", "ProxyEnable", "/t", "REG_DWORD", "/d", "1", "/f"); err != nil {
 			return err
 		}
+		return runCommand(ctx, "reg", "add", regPath, "/v", "ProxyServer", "/t", "REG_SZ", "/d", server, "/f")
+	}
+	return runCommand(ctx, "reg", "add", regPath, "/v", "ProxyEnable", "/t", "REG_DWORD", "/d", "0", "/f")
+}
 
-		cmdServer := exec.Command("reg", "add", regPath, "/v", "ProxyServer", "/t", "REG_SZ", "/d", server, "/f")
-		if _, err := cmdServer.CombinedOutput(); err != nil {
-			return err
-		}
-	} else {
-		cmdDisable := exec.Command("reg", "add", regPath, "/v", "ProxyEnable", "/t", "REG_DWORD", "/d", "0", "/f")
-		if _, err := cmdDisable.CombinedOutput(); err != nil {
-			return err
-		}
+// runCommand runs a system command and logs it. On failure the error includes
+// the command's output, which is where netsh and reg explain what went wrong.
+func runCommand(ctx context.Context, name string, args ...string) error {
+	output, err := exec.CommandContext(ctx, name, args...).CombinedOutput()
+	out := strings.TrimSpace(string(output))
+	if err != nil {
+		slog.Error("command failed", logging.ToolKey, toolName, "cmd", name, "args", args, "err", err, "output", out)
+		return fmt.Errorf("%s %s: %w: %s", name, strings.Join(args[:min(2, len(args))], " "), err, out)
 	}
+	slog.Debug("command succeeded", logging.ToolKey, toolName, "cmd", name, "args", args, "output", out)
 	return nil
 }
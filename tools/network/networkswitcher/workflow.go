@@ -0,0 +1,60 @@
+package networkswitcher
+
+import (
+	"context"
+
+	"github.com/Lec7ral/MultiTool/jobs"
+	"github.com/Lec7ral/MultiTool/tools"
+	"github.com/Lec7ral/MultiTool/tools/profiles"
+)
+
+// --- Workflow Operations ---
+
+var operations = []tools.Operation{
+	{
+		Name:  "apply",
+		Title: "Apply profile",
+		Params: []tools.Param{
+			{Key: "profile", Label: "Profile", Required: true, Options: profileNames},
+		},
+		Run: runApplyOperation,
+	},
+	{
+		Name:   "list",
+		Title:  "List profiles",
+		Output: tools.Text,
+		Run:    runListOperation,
+	},
+}
+
+// profileNames lists the saved profiles for the workflow editor.
+func profileNames() []string {
+	loaded, err := profiles.LoadProfiles()
+	if err != nil {
+		return nil
+	}
+	names := make([]string, len(loaded))
+	for i, p := range loaded {
+		names[i] = p.Name
+	}
+	return names
+}
+
+// runApplyOperation applies a profile. It produces nothing, so the data it got
+// goes on to the next step.
+func runApplyOperation(ctx context.Context, in tools.Data, params map[string]string, r jobs.Reporter) (tools.Data, error) {
+	p, err := FindProfile(params["profile"])
+	if err != nil {
+		return tools.Data{}, err
+	}
+	return tools.Data{}, ApplyProfile(ctx, p, r)
+}
+
+// runListOperation describes the saved profiles, one per line.
+func runListOperation(ctx context.Context, _ tools.Data, _ map[string]string, _ jobs.Reporter) (tools.Data, error) {
+	loaded, err := profiles.LoadProfiles()
+	if err != nil {
+		return tools.Data{}, err
+	}
+	return tools.TextData(profileList(loaded).String()), nil
+}
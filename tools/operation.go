@@ -0,0 +1,54 @@
+package tools
+
+import (
+	"context"
+
+	"github.com/Lec7ral/MultiTool/jobs"
+)
+
+// DataKind es el tipo de los datos que pasan de un paso a otro de un flujo de
+// trabajo (ver el paquete workflow).
+type DataKind string
+
+const (
+	NoData DataKind = ""      // La operación no recibe o no produce nada.
+	Files  DataKind = "files" // Lista de rutas de archivos.
+	Text   DataKind = "text"  // Texto libre.
+)
+
+// Data son los datos que recibe y produce una operación.
+type Data struct {
+	Kind  DataKind `json:"kind,omitempty"`
+	Files []string `json:"files,omitempty"`
+	Text  string   `json:"text,omitempty"`
+}
+
+// FilesData y TextData construyen un Data del tipo correspondiente.
+func FilesData(files ...string) Data { return Data{Kind: Files, Files: files} }
+func TextData(text string) Data      { return Data{Kind: Text, Text: text} }
+
+// Param es un parámetro de una operación, que se fija al definir el paso.
+type Param struct {
+	Key         string
+	Label       string
+	Description string
+	Required    bool
+	Options     func() []string // Valores posibles, si es una lista cerrada (opcional).
+}
+
+// Operation es algo que una herramienta sabe hacer sin su interfaz, y que por
+// eso puede ser un paso de un flujo de trabajo. Input es el tipo de datos que
+// espera del paso anterior (NoData si no necesita nada) y Output el que produce.
+// Una operación que no produce nada (Output NoData) deja pasar al paso siguiente
+// los datos que recibió.
+type Operation struct {
+	Name   string // Identificador dentro de la herramienta, p.ej. "merge".
+	Title  string
+	Input  DataKind
+	Output DataKind
+	Params []Param
+
+	// Run ejecuta la operación. params trae un valor por cada Param; los no
+	// obligatorios pueden venir vacíos.
+	Run func(ctx context.Context, in Data, params map[string]string, r jobs.Reporter) (Data, error)
+}
@@ -0,0 +1,33 @@
+package tools
+
+import (
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+)
+
+// PanicError es el error en que se convierte un pánico dentro de una herramienta.
+type PanicError struct {
+	Tool  string
+	Value any    // Valor pasado a panic.
+	Stack string // Traza de la goroutine en el momento del pánico.
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("%s crashed: %v", e.Tool, e.Value)
+}
+
+// Safely ejecuta fn, código de la herramienta tool, y convierte un pánico en un
+// *PanicError que además queda registrado en el log con su traza. Así un fallo
+// en una herramienta no tumba la aplicación entera.
+func Safely(tool string, fn func()) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			perr := &PanicError{Tool: tool, Value: r, Stack: string(debug.Stack())}
+			slog.Error("tool panicked", "tool", tool, "panic", fmt.Sprint(r), "stack", perr.Stack)
+			err = perr
+		}
+	}()
+	fn()
+	return nil
+}
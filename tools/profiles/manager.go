@@ -3,7 +3,8 @@ package profiles
 import (
 	"encoding/json"
 	"os"
-	"path/filepath"
+
+	"github.com/Lec7ral/MultiTool/config"
 )
 
 // Profile defines the structure for a network configuration profile.
@@ -14,18 +15,7 @@ type Profile struct {
 	ProxyServer     string `json:"proxyServer"`
 }
 
-var profilesFilePath string
-
-func init() {
-	configDir, err := os.UserConfigDir()
-	if err != nil {
-		// Fallback if config dir is not found
-		configDir = "."
-	}
-	appConfigDir := filepath.Join(configDir, "MultiTool")
-	os.MkdirAll(appConfigDir, os.ModePerm)
-	profilesFilePath = filepath.Join(appConfigDir, "profiles.json")
-}
+var profilesFilePath = config.Path("profiles.json")
 
 // LoadProfiles reads the profiles from the config file.
 // If the file doesn't exist, it creates default profiles.
@@ -53,11 +43,17 @@ func LoadProfiles() ([]Profile, error) {
 	return profiles, nil
 }
 
-// SaveProfiles writes the given profiles to the config file.
+// SaveProfiles writes the given profiles to the config file. It writes a
+// temporary file and renames it, so an interrupted save never truncates the
+// existing profiles.
 func SaveProfiles(profiles []Profile) error {
 	data, err := json.MarshalIndent(profiles, "", "  ")
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(profilesFilePath, data, 0644)
+	tmp := profilesFilePath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, profilesFilePath)
 }
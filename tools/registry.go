@@ -1,97 +1,283 @@
 package tools
 
-import "fyne.io/fyne/v2"
+import (
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"github.com/Lec7ral/MultiTool/settings"
+)
 
 // Tool defines the interface for all tools in the application.
+//
+// GetUI may be called more than once on the same instance, for example when the
+// tool is moved to its own window and back. Only the last UI is shown, so a tool
+// must keep its state outside the widgets and rebuild them from it.
 type Tool interface {
 	GetName() string
 	GetDescription() string
 	GetCategory() string
 	GetIcon() fyne.Resource
-	GetUI(fyne.Window) fyne.CanvasObject
-}
-
-// FileDropper is an optional interface for tools that can handle dropped files.
-type FileDropper interface {
-	OnFilesDropped(files []string)
+	GetUI(*ToolContext) fyne.CanvasObject
 }
 
 // ToolDescriptor contiene la información estática de una herramienta y cómo crearla.
 type ToolDescriptor struct {
 	Name        string
+	Description string
 	Category    string
 	Icon        fyne.Resource
-	Constructor func() Tool // Función para crear la instancia completa de la herramienta
+	Constructor func() Tool      // Función para crear la instancia completa de la herramienta
+	Commands    []Command        // Subcomandos de línea de comandos (opcional)
+	Settings    []settings.Field // Ajustes de la herramienta para la pantalla de ajustes (opcional)
+	Operations  []Operation      // Operaciones que pueden encadenarse en flujos de trabajo (opcional)
+	Endpoints   []Endpoint       // Rutas de la API local de automatización (opcional)
+	Systray     SystrayItems     // Entradas del menú de la bandeja del sistema (opcional)
+	Shortcuts   []Shortcut       // Atajos de teclado; la herramienta implementa ShortcutHandler (opcional)
+	Actions     ActionList       // Acciones de la paleta de comandos; la herramienta implementa ActionHandler (opcional)
+
+	// AcceptedTypes son las extensiones (".pdf") o tipos MIME ("application/pdf",
+	// "image/*") de los archivos que acepta la herramienta, que debe implementar
+	// FileDropper (opcional).
+	AcceptedTypes []string
 }
 
 // ToolRegistry gestiona los descriptores de herramientas y un caché de instancias.
+// Puede usarse desde varias goroutines (la interfaz, la bandeja del sistema, la
+// API de automatización...): cada herramienta se construye una sola vez aunque
+// varias la pidan a la vez.
 type ToolRegistry struct {
+	mu              sync.Mutex
 	toolDescriptors map[string]ToolDescriptor
 	toolInstances   map[string]Tool
+	loading         map[string]*pendingLoad // Herramientas que se están construyendo.
+	lastUsed        map[string]time.Time
+	categories      map[string]Category
 	order           []string
 }
 
+// pendingLoad es una construcción en curso. Quien llega mientras tanto espera a
+// que done se cierre y recibe el mismo resultado.
+type pendingLoad struct {
+	done chan struct{}
+	tool Tool
+	err  error
+}
+
 func NewToolRegistry() *ToolRegistry {
-	return &ToolRegistry{
+	tr := &ToolRegistry{
 		toolDescriptors: make(map[string]ToolDescriptor),
 		toolInstances:   make(map[string]Tool),
+		loading:         make(map[string]*pendingLoad),
+		lastUsed:        make(map[string]time.Time),
+		categories:      make(map[string]Category),
 		order:           make([]string, 0),
 	}
+	for _, c := range defaultCategories() {
+		tr.RegisterCategory(c)
+	}
+	return tr
 }
 
-// Register registra un descriptor de herramienta.
+// Register registra un descriptor de herramienta. Si su categoría no existe,
+// se crea automáticamente.
 func (tr *ToolRegistry) Register(descriptor ToolDescriptor) {
+	if descriptor.Category == "" {
+		descriptor.Category = "Other"
+	}
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	tr.ensureCategory(descriptor.Category)
+
 	if _, exists := tr.toolDescriptors[descriptor.Name]; !exists {
 		tr.order = append(tr.order, descriptor.Name)
 	}
 	tr.toolDescriptors[descriptor.Name] = descriptor
 }
 
-// Get obtiene una instancia de la herramienta, creándola si es necesario (carga perezosa).
-func (tr *ToolRegistry) Get(name string) Tool {
+// Load obtiene una instancia de la herramienta, creándola si es necesario (carga
+// perezosa). Tras construirla se llama a su Init, si lo implementa. Un pánico en
+// el constructor o en Init se devuelve como *PanicError.
+//
+// La construcción se hace sin bloquear el registro, y si otras llamadas piden la
+// misma herramienta mientras tanto, esperan a que termine y reciben la misma
+// instancia (o el mismo error).
+func (tr *ToolRegistry) Load(name string) (Tool, error) {
+	tr.mu.Lock()
 	if instance, ok := tr.toolInstances[name]; ok {
-		return instance
+		tr.lastUsed[name] = time.Now()
+		tr.mu.Unlock()
+		return instance, nil
+	}
+	if pending, ok := tr.loading[name]; ok {
+		tr.mu.Unlock()
+		<-pending.done
+		return pending.tool, pending.err
 	}
+	descriptor, ok := tr.toolDescriptors[name]
+	if !ok {
+		tr.mu.Unlock()
+		return nil, fmt.Errorf("tool %q is not registered", name)
+	}
+	pending := &pendingLoad{done: make(chan struct{})}
+	tr.loading[name] = pending
+	tr.mu.Unlock()
+
+	pending.tool, pending.err = construct(name, descriptor)
+
+	tr.mu.Lock()
+	delete(tr.loading, name)
+	if pending.err == nil {
+		tr.toolInstances[name] = pending.tool
+		tr.lastUsed[name] = time.Now()
+	}
+	tr.mu.Unlock()
+	close(pending.done)
+	return pending.tool, pending.err
+}
 
-	if descriptor, ok := tr.toolDescriptors[name]; ok {
-		instance := descriptor.Constructor() // Llama a la función constructora.
-		tr.toolInstances[name] = instance
-		return instance
+// construct crea la instancia de una herramienta y llama a su Init.
+func construct(name string, descriptor ToolDescriptor) (Tool, error) {
+	var instance Tool
+	var initErr error
+	if err := Safely(name, func() {
+		instance = descriptor.Constructor() // Llama a la función constructora.
+		if initializer, ok := instance.(Initializer); ok {
+			initErr = initializer.Init()
+		}
+	}); err != nil {
+		return nil, err
+	}
+	if initErr != nil {
+		return nil, fmt.Errorf("init %s: %w", name, initErr)
 	}
+	return instance, nil
+}
+
+// Get es como Load, pero registra el error en el log y devuelve nil.
+func (tr *ToolRegistry) Get(name string) Tool {
+	instance, err := tr.Load(name)
+	if err != nil {
+		slog.Error("failed to load tool", "tool", name, "err", err)
+		return nil
+	}
+	return instance
+}
 
-	return nil
+// IsLoaded indica si la herramienta tiene una instancia en caché.
+func (tr *ToolRegistry) IsLoaded(name string) bool {
+	_, ok := tr.Loaded(name)
+	return ok
+}
+
+// Loaded devuelve la instancia en caché de la herramienta, si la hay, sin crearla
+// ni contarlo como un uso (a diferencia de Load).
+func (tr *ToolRegistry) Loaded(name string) (Tool, bool) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	instance, ok := tr.toolInstances[name]
+	return instance, ok
+}
+
+// Unload elimina la instancia del caché y después llama a su Dispose (si existe).
+// La próxima llamada a Load creará una instancia nueva. Un pánico en Dispose solo
+// se registra.
+func (tr *ToolRegistry) Unload(name string) {
+	tr.mu.Lock()
+	instance, ok := tr.toolInstances[name]
+	delete(tr.toolInstances, name)
+	delete(tr.lastUsed, name)
+	tr.mu.Unlock()
+	if !ok {
+		return
+	}
+	if disposer, ok := instance.(Disposer); ok {
+		Safely(name, disposer.Dispose)
+	}
+}
+
+// EvictIdle descarga las herramientas que no se han usado en maxIdle, salvo las
+// que keep indique o las que declaren (con Evictable) que no pueden descargarse.
+// Devuelve los nombres de las herramientas descargadas.
+func (tr *ToolRegistry) EvictIdle(maxIdle time.Duration, keep func(name string) bool) []string {
+	// keep y CanEvict se llaman sin bloquear el registro, porque pueden volver a
+	// usarlo. Por eso solo se descarga una herramienta si sigue siendo la misma
+	// instancia y nadie la ha usado mientras tanto.
+	type candidate struct {
+		name     string
+		instance Tool
+		lastUsed time.Time
+	}
+	var candidates []candidate
+	tr.mu.Lock()
+	for name, instance := range tr.toolInstances {
+		if time.Since(tr.lastUsed[name]) >= maxIdle {
+			candidates = append(candidates, candidate{name, instance, tr.lastUsed[name]})
+		}
+	}
+	tr.mu.Unlock()
+
+	var evicted []string
+	for _, c := range candidates {
+		if keep != nil && keep(c.name) {
+			continue
+		}
+		canEvict := true
+		if e, ok := c.instance.(Evictable); ok {
+			Safely(c.name, func() { canEvict = e.CanEvict() })
+		}
+		if !canEvict {
+			continue
+		}
+
+		tr.mu.Lock()
+		unused := tr.toolInstances[c.name] == c.instance && tr.lastUsed[c.name].Equal(c.lastUsed)
+		if unused {
+			delete(tr.toolInstances, c.name)
+			delete(tr.lastUsed, c.name)
+		}
+		tr.mu.Unlock()
+		if !unused {
+			continue
+		}
+		if disposer, ok := c.instance.(Disposer); ok {
+			Safely(c.name, disposer.Dispose)
+		}
+		evicted = append(evicted, c.name)
+	}
+	return evicted
+}
+
+// DisposeAll descarga todas las herramientas, por ejemplo al cerrar la ventana.
+func (tr *ToolRegistry) DisposeAll() {
+	tr.mu.Lock()
+	names := make([]string, 0, len(tr.toolInstances))
+	for name := range tr.toolInstances {
+		names = append(names, name)
+	}
+	tr.mu.Unlock()
+	for _, name := range names {
+		tr.Unload(name)
+	}
+}
+
+// Descriptor devuelve el descriptor registrado con ese nombre.
+func (tr *ToolRegistry) Descriptor(name string) (ToolDescriptor, bool) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	descriptor, ok := tr.toolDescriptors[name]
+	return descriptor, ok
 }
 
 // GetAllDescriptors devuelve todos los descriptores de herramientas registrados.
 func (tr *ToolRegistry) GetAllDescriptors() []ToolDescriptor {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
 	result := make([]ToolDescriptor, 0, len(tr.order))
 	for _, name := range tr.order {
 		result = append(result, tr.toolDescriptors[name])
 	}
 	return result
 }
-
-// RegisterDefaultTools registra los descriptores de las herramientas predeterminadas.
-func RegisterDefaultTools(registry *ToolRegistry) {
-	// Para obtener la información estática (icono, categoría) sin crear la herramienta,
-	// necesitamos una forma de acceder a ella. La solución más limpia es tener
-	// una instancia "ligera" o prototipo, o simplemente definirla aquí.
-
-	// Prototipo de PDFMerger para obtener sus metadatos.
-	pdfMergerProto := NewPDFMergerTool()
-	registry.Register(ToolDescriptor{
-		Name:        pdfMergerProto.GetName(),
-		Category:    pdfMergerProto.GetCategory(),
-		Icon:        pdfMergerProto.GetIcon(),
-		Constructor: NewPDFMergerTool,
-	})
-
-	// Prototipo de NetworkSwitcher para obtener sus metadatos.
-	networkSwitcherProto := NewNetworkSwitcherTool()
-	registry.Register(ToolDescriptor{
-		Name:        networkSwitcherProto.GetName(),
-		Category:    networkSwitcherProto.GetCategory(),
-		Icon:        networkSwitcherProto.GetIcon(),
-		Constructor: NewNetworkSwitcherTool,
-	})
-}
@@ -0,0 +1,209 @@
+package tools
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2"
+)
+
+// fakeTool cuenta cuántas veces se descarta.
+type fakeTool struct {
+	name     string
+	disposed atomic.Int32
+}
+
+func (t *fakeTool) GetName() string                      { return t.name }
+func (t *fakeTool) GetDescription() string               { return "" }
+func (t *fakeTool) GetCategory() string                  { return "Test" }
+func (t *fakeTool) GetIcon() fyne.Resource               { return nil }
+func (t *fakeTool) GetUI(*ToolContext) fyne.CanvasObject { return nil }
+func (t *fakeTool) Dispose()                             { t.disposed.Add(1) }
+
+// countingRegistry devuelve un registro con n herramientas cuyo constructor es
+// lento (para que las llamadas concurrentes coincidan) y cuenta las instancias
+// creadas.
+func countingRegistry(n int) (*ToolRegistry, *atomic.Int32, func() []*fakeTool) {
+	tr := NewToolRegistry()
+	var constructed atomic.Int32
+	var mu sync.Mutex
+	var instances []*fakeTool
+	for i := range n {
+		name := fmt.Sprintf("tool%d", i)
+		tr.Register(ToolDescriptor{Name: name, Category: "Test", Constructor: func() Tool {
+			constructed.Add(1)
+			time.Sleep(10 * time.Millisecond)
+			t := &fakeTool{name: name}
+			mu.Lock()
+			instances = append(instances, t)
+			mu.Unlock()
+			return t
+		}})
+	}
+	return tr, &constructed, func() []*fakeTool {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]*fakeTool(nil), instances...)
+	}
+}
+
+func TestLoadConstructsOnce(t *testing.T) {
+	tr, constructed, _ := countingRegistry(1)
+
+	const callers = 50
+	results := make([]Tool, callers)
+	var wg sync.WaitGroup
+	for i := range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tool, err := tr.Load("tool0")
+			if err != nil {
+				t.Errorf("Load: %v", err)
+			}
+			results[i] = tool
+		}()
+	}
+	wg.Wait()
+
+	if n := constructed.Load(); n != 1 {
+		t.Fatalf("constructor called %d times, want 1", n)
+	}
+	for i, tool := range results {
+		if tool != results[0] {
+			t.Fatalf("caller %d got a different instance", i)
+		}
+	}
+}
+
+func TestLoadSharesConstructionError(t *testing.T) {
+	tr := NewToolRegistry()
+	var constructed atomic.Int32
+	tr.Register(ToolDescriptor{Name: "broken", Constructor: func() Tool {
+		constructed.Add(1)
+		time.Sleep(10 * time.Millisecond)
+		panic("boom")
+	}})
+
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var panicErr *PanicError
+			if _, err := tr.Load("broken"); !errors.As(err, &panicErr) {
+				t.Errorf("Load error = %v, want a *PanicError", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := constructed.Load(); n != 1 {
+		t.Fatalf("constructor called %d times, want 1", n)
+	}
+	if tr.IsLoaded("broken") {
+		t.Fatal("a failed tool must not be cached")
+	}
+	// Un fallo no se recuerda: la siguiente llamada lo vuelve a intentar.
+	tr.Load("broken")
+	if n := constructed.Load(); n != 2 {
+		t.Fatalf("constructor called %d times after retrying, want 2", n)
+	}
+}
+
+func TestLoadUnknownTool(t *testing.T) {
+	tr := NewToolRegistry()
+	if _, err := tr.Load("missing"); err == nil {
+		t.Fatal("Load of an unregistered tool succeeded")
+	}
+}
+
+// TestConcurrentUse mezcla todas las operaciones del registro desde varias
+// goroutines. Sirve sobre todo con go test -race.
+func TestConcurrentUse(t *testing.T) {
+	const n = 4
+	tr, _, instances := countingRegistry(n)
+
+	var wg sync.WaitGroup
+	for g := range 16 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 50 {
+				name := fmt.Sprintf("tool%d", (g+i)%n)
+				switch i % 7 {
+				case 0, 1:
+					if tr.Get(name) == nil {
+						t.Errorf("Get(%s) = nil", name)
+					}
+				case 2:
+					tr.IsLoaded(name)
+					tr.Loaded(name)
+				case 3:
+					tr.Unload(name)
+				case 4:
+					tr.EvictIdle(0, func(n string) bool { return n == "tool0" })
+				case 5:
+					tr.GetAllDescriptors()
+					tr.GetCategories()
+					tr.GetDescriptorsByCategory("Test")
+				case 6:
+					tr.Register(ToolDescriptor{Name: fmt.Sprintf("extra%d", g), Category: fmt.Sprintf("Extra%d", g),
+						Constructor: func() Tool { return &fakeTool{} }})
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	tr.DisposeAll()
+
+	// Cada instancia creada se descarta exactamente una vez.
+	for _, tool := range instances() {
+		if n := tool.disposed.Load(); n != 1 {
+			t.Errorf("%s instance disposed %d times, want 1", tool.name, n)
+		}
+	}
+}
+
+func TestEvictIdleKeepsToolsInUse(t *testing.T) {
+	tr, _, _ := countingRegistry(2)
+	tr.Load("tool0")
+	tr.Load("tool1")
+
+	evicted := tr.EvictIdle(time.Hour, nil)
+	if len(evicted) != 0 {
+		t.Fatalf("evicted %v, want nothing before maxIdle", evicted)
+	}
+	evicted = tr.EvictIdle(0, func(name string) bool { return name == "tool1" })
+	if len(evicted) != 1 || evicted[0] != "tool0" {
+		t.Fatalf("evicted %v, want [tool0]", evicted)
+	}
+	if tr.IsLoaded("tool0") || !tr.IsLoaded("tool1") {
+		t.Fatal("wrong tools left loaded")
+	}
+
+	// Una herramienta que se descarga y se vuelve a cargar entre la búsqueda y
+	// la descarga es otra instancia, aunque coincida la hora de su último uso.
+	old, _ := tr.Load("tool1")
+	tr.mu.Lock()
+	lastUsed := tr.lastUsed["tool1"]
+	tr.mu.Unlock()
+	evicted = tr.EvictIdle(0, func(name string) bool {
+		tr.Unload(name)
+		tr.Load(name)
+		tr.mu.Lock()
+		tr.lastUsed[name] = lastUsed
+		tr.mu.Unlock()
+		return false
+	})
+	if len(evicted) != 0 {
+		t.Fatalf("evicted %v, want nothing after reloading", evicted)
+	}
+	if current, ok := tr.Loaded("tool1"); !ok || current == old {
+		t.Fatal("the reloaded tool1 was evicted")
+	}
+}
@@ -0,0 +1,25 @@
+package tools
+
+// Atajos que la aplicación dirige a la herramienta visible. Sus teclas son las de
+// la aplicación (Ctrl+O y Ctrl+Enter por defecto), no las de Shortcut.Keys.
+const (
+	ShortcutOpen    = "open"    // Añadir o abrir archivos.
+	ShortcutPrimary = "primary" // Acción principal de la herramienta.
+)
+
+// Shortcut es un atajo de teclado de una herramienta, declarado en
+// ToolDescriptor.Shortcuts para poder listarlo en los ajustes y en la referencia
+// de atajos sin construir la herramienta. Solo funciona mientras la herramienta
+// es la visible (o en su propia ventana).
+type Shortcut struct {
+	ID    string // Identifica el atajo en los ajustes, donde el usuario puede cambiar sus teclas.
+	Title string // En inglés; se traduce con i18n.T al mostrarlo.
+	Keys  string // Combinación por defecto, como "Ctrl+Shift+S". Debe llevar Ctrl, Alt o Super.
+}
+
+// ShortcutHandler lo implementan las herramientas que declaran atajos. RunShortcut
+// recibe el ID del atajo pulsado; solo se llama con la herramienta cargada y su UI
+// ya construida.
+type ShortcutHandler interface {
+	RunShortcut(id string)
+}
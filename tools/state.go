@@ -0,0 +1,14 @@
+package tools
+
+import "encoding/json"
+
+// StateSaver permite guardar el trabajo de una herramienta en un espacio de
+// trabajo (ver el paquete workspace) y recuperarlo en otra sesión.
+//
+// SaveState devuelve el estado serializado; RestoreState lo sustituye por uno
+// guardado antes, tal vez con la UI ya construida, así que la herramienta debe
+// actualizarla. Los dos se llaman desde la goroutine de la interfaz.
+type StateSaver interface {
+	SaveState() (json.RawMessage, error)
+	RestoreState(state json.RawMessage) error
+}
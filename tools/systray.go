@@ -0,0 +1,22 @@
+package tools
+
+import "fyne.io/fyne/v2"
+
+// SystrayMenu es el menú de la bandeja del sistema.
+type SystrayMenu interface {
+	// Refresh reconstruye el menú, volviendo a pedir sus entradas a las
+	// herramientas. Puede llamarse desde cualquier goroutine.
+	Refresh()
+}
+
+// SystrayItems construye las entradas (o submenús) que una herramienta añade al
+// menú de la bandeja del sistema. Se declara en ToolDescriptor.Systray.
+//
+// La bandeja no crea instancias de las herramientas: las entradas deben trabajar
+// con el backend de la herramienta y con ctx (que no tiene ventana). Para
+// compartir estado con la ventana, como una lista de archivos recientes, hay que
+// guardarlo en ctx.Settings y llamar a ctx.Systray.Refresh() al cambiarlo.
+//
+// La bandeja ya protege con ctx.Safe las acciones de las entradas y de sus
+// submenús: la herramienta no tiene que hacerlo.
+type SystrayItems func(ctx *ToolContext) []*fyne.MenuItem
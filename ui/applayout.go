@@ -1,133 +1,438 @@
 package ui
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/url"
+	"time"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/dialog"
 	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
+	"github.com/Lec7ral/MultiTool/i18n"
 	"github.com/Lec7ral/MultiTool/tools"
 )
 
-// CreateAppLayout construye y devuelve el layout principal de la aplicación.
-func CreateAppLayout() (fyne.CanvasObject, func(fyne.Window)) {
-	toolRegistry := tools.NewToolRegistry()
-	tools.RegisterDefaultTools(toolRegistry)
+// AppVersion es la versión de MultiTool que muestran "About" y el diagnóstico.
+const AppVersion = "1.0.0"
 
-	// Agrupar descriptores de herramientas por categoría
-	categories := make(map[string][]tools.ToolDescriptor)
-	categoryOrder := []string{"System", "Files", "Text", "Network"} // Orden deseado
-	for _, descriptor := range toolRegistry.GetAllDescriptors() {
-		categories[descriptor.Category] = append(categories[descriptor.Category], descriptor)
-	}
+// Las herramientas que no se usan durante idleTimeout se descargan para liberar memoria.
+const (
+	idleTimeout   = 10 * time.Minute
+	evictInterval = time.Minute
+)
 
-	categoryIcons := map[string]fyne.Resource{
-		"System":  theme.SettingsIcon(),
-		"Files":   theme.FolderIcon(),
-		"Text":    theme.DocumentIcon(),
-		"Network": theme.ComputerIcon(),
-	}
+// AppLayout es el layout principal de una ventana: pestañas de categorías,
+// pestañas de herramientas y barra de estado.
+type AppLayout struct {
+	Content fyne.CanvasObject
 
-	// --- Pestañas de Categorías (Nivel Superior) ---
-	categoryTabs := container.NewAppTabs()
+	window       fyne.Window
+	services     *AppServices
+	registry     *tools.ToolRegistry
+	status       *statusBar
+	contexts     map[string]*tools.ToolContext
+	categoryTabs *container.AppTabs
+	categories   map[*container.TabItem]*categoryView
+	descriptors  map[*container.TabItem]tools.ToolDescriptor
+	failed       map[string]error           // Herramientas que fallaron y muestran el panel de error.
+	popouts      map[string]*popOutWindow   // Herramientas abiertas en su propia ventana.
+	toolState    map[string]json.RawMessage // Último estado guardado de cada herramienta (ver workspace.go).
+	active       string                     // Herramienta visible en este momento.
+	appKeys      *shortcutSet               // Atajos de la aplicación.
+	toolKeys     *shortcutSet               // Atajos de la herramienta visible.
+	stopEviction chan struct{}
+	cleanups     []func() // Se llaman en Dispose.
+}
 
-	for _, categoryName := range categoryOrder {
-		if descriptorsInCat, ok := categories[categoryName]; ok {
+// categoryView contiene las pestañas de herramientas de una categoría y el panel
+// donde se muestra la herramienta seleccionada.
+type categoryView struct {
+	name     string // Nombre de la categoría (las pestañas muestran el traducido).
+	toolTabs *container.AppTabs
+	content  *fyne.Container
+	loaded   string // Herramienta cuya UI está ahora en content.
+}
 
-			// --- Contenido de la Herramienta (Panel Derecho) ---
-			toolContent := container.NewMax()
+// CreateAppLayout construye el layout principal de la aplicación para la ventana w
+// y configura sus callbacks (como OnDropped). Hay que llamar a Dispose cuando la
+// ventana se cierre.
+func CreateAppLayout(w fyne.Window, services *AppServices) *AppLayout {
+	l := &AppLayout{
+		window:   w,
+		services: services,
+		// Las herramientas se registran solas desde su init() (ver tools/builtin).
+		registry:     tools.NewDefaultRegistry(),
+		status:       newStatusBar(),
+		contexts:     make(map[string]*tools.ToolContext),
+		categoryTabs: container.NewAppTabs(),
+		categories:   make(map[*container.TabItem]*categoryView),
+		descriptors:  make(map[*container.TabItem]tools.ToolDescriptor),
+		failed:       make(map[string]error),
+		popouts:      make(map[string]*popOutWindow),
+		toolState:    make(map[string]json.RawMessage),
+		appKeys:      &shortcutSet{canvas: w.Canvas()},
+		toolKeys:     &shortcutSet{canvas: w.Canvas()},
+		stopEviction: make(chan struct{}),
+	}
 
-			// --- Pestañas de Herramientas (Panel Izquierdo) ---
-			toolTabs := container.NewAppTabs()
-			toolTabs.SetTabLocation(container.TabLocationLeading)
+	// La categoría y la herramienta seleccionadas se recuerdan entre sesiones.
+	windowState := services.Settings.Section(windowSection)
 
-			// Mapa para asociar cada TabItem con su descriptor de herramienta
-			tabToDescriptorMap := make(map[*container.TabItem]tools.ToolDescriptor)
+	// --- Pestañas de Categorías (Nivel Superior) ---
+	// El registro solo devuelve categorías con herramientas, ya ordenadas, así que
+	// toda categoría mostrada tiene al menos una pestaña.
+	for _, category := range l.registry.GetCategories() {
+		cv := &categoryView{
+			name: category.Name,
+			// --- Contenido de la Herramienta (Panel Derecho) ---
+			content: container.NewMax(),
+			// --- Pestañas de Herramientas (Panel Izquierdo) ---
+			toolTabs: container.NewAppTabs(),
+		}
+		cv.toolTabs.SetTabLocation(container.TabLocationLeading)
 
-			for _, descriptor := range descriptorsInCat {
-				// El contenido inicial de la pestaña está vacío. La herramienta no se crea aquí.
-				tabItem := container.NewTabItemWithIcon(descriptor.Name, descriptor.Icon, container.NewWithoutLayout())
-				toolTabs.Append(tabItem)
-				tabToDescriptorMap[tabItem] = descriptor
+		lastTool := windowState.String(keyToolIn+category.Name, "")
+		for _, descriptor := range l.registry.GetDescriptorsByCategory(category.Name) {
+			// El contenido inicial de la pestaña está vacío. La herramienta no se crea aquí.
+			tabItem := container.NewTabItemWithIcon(i18n.T(descriptor.Name), descriptor.Icon, container.NewWithoutLayout())
+			cv.toolTabs.Append(tabItem)
+			l.descriptors[tabItem] = descriptor
+			if descriptor.Name == lastTool {
+				cv.toolTabs.Select(tabItem)
 			}
+		}
 
-			toolTabs.OnSelected = func(selectedTab *container.TabItem) {
-				if selectedTab == nil {
-					return
-				}
-				if descriptor, ok := tabToDescriptorMap[selectedTab]; ok {
-					// Obtenemos la herramienta (se crea aquí si es la primera vez).
-					tool := toolRegistry.Get(descriptor.Name)
-					if tool != nil {
-						toolContent.Objects = []fyne.CanvasObject{tool.GetUI(nil)}
-						toolContent.Refresh()
-					}
-				}
-			}
+		// La descripción de la categoría encabeza su pestaña.
+		var header fyne.CanvasObject
+		if category.Description != "" {
+			header = widget.NewLabelWithStyle(i18n.T(category.Description), fyne.TextAlignLeading, fyne.TextStyle{Italic: true})
+		}
+		layout := container.NewBorder(header, nil, cv.toolTabs, nil, cv.content)
+		categoryTab := container.NewTabItemWithIcon(i18n.T(category.Name), category.Icon, layout)
+		l.categoryTabs.Append(categoryTab)
+		l.categories[categoryTab] = cv
 
-			// Cargar la primera herramienta de la categoría por defecto.
-			if len(toolTabs.Items) > 0 {
-				// Seleccionamos la primera pestaña.
-				toolTabs.SelectIndex(0)
-
-				// Y cargamos su contenido manualmente para asegurar que la UI inicial aparezca,
-				// ya que SelectIndex() no siempre dispara OnSelected() al inicio.
-				firstTab := toolTabs.Items[0]
-				if descriptor, ok := tabToDescriptorMap[firstTab]; ok {
-					tool := toolRegistry.Get(descriptor.Name)
-					if tool != nil {
-						toolContent.Objects = []fyne.CanvasObject{tool.GetUI(nil)}
-						toolContent.Refresh()
-					}
-				}
-			} else {
-				// Si no hay herramientas en la categoría, limpiamos el contenido.
-				toolContent.Objects = nil
-				toolContent.Refresh()
+		if category.Name == windowState.String(keyCategory, "") {
+			l.categoryTabs.Select(categoryTab)
+		}
+
+		cv.toolTabs.OnSelected = func(tab *container.TabItem) {
+			windowState.SetString(keyToolIn+cv.name, l.descriptors[tab].Name)
+			if l.categoryTabs.Selected() == categoryTab {
+				l.showSelectedTool(cv)
 			}
+		}
+	}
 
-			layout := container.NewBorder(nil, nil, toolTabs, nil, toolContent)
-			categoryTabs.Append(container.NewTabItemWithIcon(categoryName, categoryIcons[categoryName], layout))
+	// La herramienta solo se crea cuando su categoría se muestra por primera vez.
+	l.categoryTabs.OnSelected = func(tab *container.TabItem) {
+		if cv, ok := l.categories[tab]; ok {
+			windowState.SetString(keyCategory, cv.name)
+			l.showSelectedTool(cv)
 		}
 	}
+	if cv, ok := l.categories[l.categoryTabs.Selected()]; ok {
+		l.showSelectedTool(cv)
+	}
 
 	// --- Lógica de Arrastrar y Soltar (Drag and Drop) ---
-	setupWindowCallbacks := func(w fyne.Window) {
-		w.SetOnDropped(func(p fyne.Position, uris []fyne.URI) {
-			if categoryTabs.Selected().Text == "Files" {
-				// Obtenemos la instancia de PDF Merger solo cuando se necesita.
-				pdfMergerInstance := toolRegistry.Get("PDF Merger")
-				if dropper, ok := pdfMergerInstance.(tools.FileDropper); ok {
-					var filePaths []string
-					for _, u := range uris {
-						filePaths = append(filePaths, u.Path())
-					}
-					dropper.OnFilesDropped(filePaths)
-				}
-			}
-		})
-	}
+	// Los archivos van a la herramienta activa si implementa tools.FileDropper.
+	w.SetOnDropped(func(p fyne.Position, uris []fyne.URI) {
+		if l.active == "" || l.failed[l.active] != nil {
+			return
+		}
+		filePaths := make([]string, 0, len(uris))
+		for _, u := range uris {
+			filePaths = append(filePaths, localPath(u))
+		}
+		l.routeDroppedFiles(w, l.status, l.active, l.registry.Get(l.active), filePaths)
+	})
 
 	// --- Barra de Estado Inferior ---
 	projectURL, _ := url.Parse("https://github.com/Lec7ral/MultiTool")
-	aboutButton := widget.NewButton("About", func() {
-		if fyne.CurrentApp().Driver().AllWindows() != nil && len(fyne.CurrentApp().Driver().AllWindows()) > 0 {
-			activeWindow := fyne.CurrentApp().Driver().AllWindows()[0]
-			aboutContent := container.NewVBox(
-				widget.NewLabelWithStyle("MultiTool v1.0.0", fyne.TextAlignCenter, fyne.TextStyle{Bold: true}),
-				widget.NewLabelWithStyle("Developed by Lec7ral", fyne.TextAlignCenter, fyne.TextStyle{}),
-				widget.NewHyperlinkWithStyle("Project on GitHub", projectURL, fyne.TextAlignCenter, fyne.TextStyle{}),
-			)
-			dialog.ShowCustom("About", "Close", aboutContent, activeWindow)
+	aboutButton := widget.NewButton(i18n.T("About"), func() {
+		aboutContent := container.NewVBox(
+			widget.NewLabelWithStyle("MultiTool v"+AppVersion, fyne.TextAlignCenter, fyne.TextStyle{Bold: true}),
+			widget.NewLabelWithStyle(i18n.T("Developed by %s", "Lec7ral"), fyne.TextAlignCenter, fyne.TextStyle{}),
+			widget.NewHyperlinkWithStyle(i18n.T("Project on GitHub"), projectURL, fyne.TextAlignCenter, fyne.TextStyle{}),
+		)
+		dialog.ShowCustom(i18n.T("About"), i18n.T("Close"), aboutContent, w)
+	})
+
+	settingsButton := widget.NewButton(i18n.T("Settings"), l.showSettings)
+
+	jobsButton, removeJobsListener := newJobsButton(w, services.Jobs)
+	l.cleanups = append(l.cleanups, removeJobsListener)
+
+	logsButton := widget.NewButton(i18n.T("Logs"), showLogViewer)
+	workflowsButton := widget.NewButton(i18n.T("Workflows"), func() { showWorkflows(services, "") })
+
+	// --- Ventana Separada ---
+	popOutButton := widget.NewButtonWithIcon("", theme.ViewFullScreenIcon(), func() {
+		if l.active != "" {
+			l.PopOut(l.active)
 		}
 	})
 
-	statusBar := container.NewBorder(nil, nil, nil, aboutButton, container.NewWithoutLayout())
+	// --- Paleta de Comandos (Ctrl+K) ---
+	searchButton := widget.NewButtonWithIcon("", theme.SearchIcon(), l.ShowPalette)
+
+	// --- Atajos de Teclado ---
+	// Se reinstalan cuando el usuario cambia sus teclas en los ajustes.
+	l.installAppShortcuts()
+	removeShortcutsListener := services.Settings.OnChange(func(section, key string) {
+		if section == shortcutsSection {
+			fyne.Do(l.installAppShortcuts)
+		}
+	})
+	l.cleanups = append(l.cleanups, removeShortcutsListener, l.appKeys.clear, l.toolKeys.clear)
+
+	statusBarArea := container.NewBorder(nil, nil, nil, container.NewHBox(searchButton, popOutButton, jobsButton, workflowsButton, logsButton, settingsButton, aboutButton), l.status.label)
 
 	// --- Layout Principal Final ---
-	mainLayout := container.NewBorder(nil, statusBar, nil, nil, categoryTabs)
+	l.Content = container.NewBorder(nil, statusBarArea, nil, nil, l.categoryTabs)
+
+	// El trabajo de la sesión anterior vuelve a las herramientas.
+	l.restoreSession()
+
+	// Si los ajustes no se pudieron cargar, se avisa una vez, en la primera ventana.
+	if err := services.SettingsError; err != nil {
+		services.SettingsError = nil
+		dialog.ShowError(errors.New(i18n.T("Your settings could not be loaded: %v", err)), w)
+	}
+
+	go l.evictLoop()
+	return l
+}
+
+// contextFor devuelve (creándolo la primera vez) el ToolContext de una herramienta.
+func (l *AppLayout) contextFor(name string) *tools.ToolContext {
+	if ctx, ok := l.contexts[name]; ok {
+		return ctx
+	}
+	ctx := newToolContext(l.window, l.status, l.services, name)
+	ctx.OnPanic = l.crashHandler(name)
+	l.contexts[name] = ctx
+	return ctx
+}
+
+// crashHandler devuelve el ToolContext.OnPanic de la herramienta name.
+func (l *AppLayout) crashHandler(name string) func(error) {
+	return func(err error) {
+		fyne.Do(func() { l.toolCrashed(name, err) })
+	}
+}
+
+// guard protege fn, código de la herramienta name que la interfaz llama
+// directamente (atajos, acciones de la paleta): un pánico muestra su panel de error.
+func (l *AppLayout) guard(name string, fn func()) func() {
+	return func() {
+		if err := tools.Safely(name, fn); err != nil {
+			l.toolCrashed(name, err)
+		}
+	}
+}
 
-	return mainLayout, setupWindowCallbacks
+// toolCrashed descarta la herramienta name tras un pánico en su código y muestra
+// su panel de error (en la pestaña, cerrando su ventana propia si la tenía).
+func (l *AppLayout) toolCrashed(name string, err error) {
+	if _, failed := l.failed[name]; failed {
+		return
+	}
+	if p, ok := l.popouts[name]; ok {
+		p.window.SetOnClosed(nil)
+		p.window.Close()
+		delete(l.popouts, name)
+	}
+	l.registry.Unload(name)
+	delete(l.contexts, name)
+	l.failed[name] = err
+	l.refreshTool(name)
+}
+
+// showSettings muestra la pantalla de ajustes, con la sección de atajos al día.
+func (l *AppLayout) showSettings() {
+	l.registerShortcutSchema()
+	showSettings(l.window, l.services.Settings)
+}
+
+// showSelectedTool muestra la herramienta seleccionada en cv, construyendo su UI
+// si todavía no existe o si la herramienta se descargó, y la marca como activa.
+func (l *AppLayout) showSelectedTool(cv *categoryView) {
+	descriptor, ok := l.descriptors[cv.toolTabs.Selected()]
+	if !ok {
+		return
+	}
+	name := descriptor.Name
+
+	_, failed := l.failed[name]
+	_, poppedOut := l.popouts[name]
+	if cv.loaded != name || !failed && !poppedOut && !l.registry.IsLoaded(name) {
+		l.loadInto(cv, name)
+	}
+	l.setActive(name)
+	l.installToolShortcuts()
+}
+
+// loadInto pone en el panel de cv la UI de la herramienta name. Si la herramienta
+// está en su propia ventana muestra un aviso, y si falla al crearse o al
+// construir su UI, un panel de error.
+func (l *AppLayout) loadInto(cv *categoryView, name string) {
+	var content fyne.CanvasObject
+	if err, failed := l.failed[name]; failed {
+		content = l.errorPanel(cv, name, err)
+	} else if _, poppedOut := l.popouts[name]; poppedOut {
+		content = l.popOutPanel(name)
+	} else if content, err = l.buildToolUI(name); err != nil {
+		l.failed[name] = err
+		content = l.errorPanel(cv, name, err)
+	}
+	cv.content.Objects = []fyne.CanvasObject{content}
+	cv.content.Refresh()
+	cv.loaded = name
+}
+
+// buildToolUI obtiene la herramienta (se crea aquí si es la primera vez) y
+// construye su UI. Si algo falla, descarta la instancia.
+func (l *AppLayout) buildToolUI(name string) (fyne.CanvasObject, error) {
+	tool, err := l.registry.Load(name)
+	if err != nil {
+		return nil, err
+	}
+	var content fyne.CanvasObject
+	if err := tools.Safely(name, func() { content = tool.GetUI(l.contextFor(name)) }); err != nil {
+		l.registry.Unload(name)
+		delete(l.contexts, name)
+		return nil, err
+	}
+	return content, nil
+}
+
+// errorPanel es lo que se muestra en lugar de una herramienta que ha fallado.
+func (l *AppLayout) errorPanel(cv *categoryView, name string, err error) fyne.CanvasObject {
+	title := widget.NewLabelWithStyle(i18n.T("%s stopped working.", i18n.T(name)), fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+	details := widget.NewLabel(err.Error())
+	details.Wrapping = fyne.TextWrapWord
+	details.Alignment = fyne.TextAlignCenter
+
+	reload := widget.NewButtonWithIcon(i18n.T("Reload tool"), theme.ViewRefreshIcon(), func() {
+		delete(l.failed, name)
+		cv.loaded = ""
+		l.showSelectedTool(cv)
+	})
+	reload.Importance = widget.HighImportance
+	logs := widget.NewButton(i18n.T("Show logs"), showLogViewer)
+
+	return container.NewCenter(container.NewVBox(
+		container.NewCenter(widget.NewIcon(theme.ErrorIcon())),
+		title,
+		details,
+		widget.NewLabelWithStyle(i18n.T("The error has been recorded in the log."), fyne.TextAlignCenter, fyne.TextStyle{Italic: true}),
+		container.NewCenter(container.NewHBox(reload, logs)),
+	))
+}
+
+// OpenTool muestra la herramienta name y devuelve su instancia y su contexto, o
+// el error por el que no se puede mostrar.
+func (l *AppLayout) OpenTool(name string) (tools.Tool, *tools.ToolContext, error) {
+	if !l.SelectTool(name) {
+		return nil, nil, fmt.Errorf("tool %q %w", name, tools.ErrNotFound)
+	}
+	if err := l.failed[name]; err != nil {
+		return nil, nil, err
+	}
+	tool, err := l.registry.Load(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	return tool, l.contextFor(name), nil
+}
+
+// SelectTool cambia a la pestaña de la herramienta name (y a la de su categoría).
+// Devuelve false si la herramienta no está en este layout.
+func (l *AppLayout) SelectTool(name string) bool {
+	for categoryTab, cv := range l.categories {
+		for _, tabItem := range cv.toolTabs.Items {
+			if l.descriptors[tabItem].Name != name {
+				continue
+			}
+			l.categoryTabs.Select(categoryTab)
+			cv.toolTabs.Select(tabItem)
+			l.showSelectedTool(cv)
+			return true
+		}
+	}
+	return false
+}
+
+// setActive avisa a la herramienta anterior de que deja de verse y a la nueva de
+// que pasa a ser la visible.
+func (l *AppLayout) setActive(name string) {
+	if name == l.active {
+		return
+	}
+	if l.active != "" && l.registry.IsLoaded(l.active) {
+		previous := l.registry.Get(l.active)
+		tools.Safely(l.active, func() { tools.Deactivate(previous) })
+	}
+	l.active = name
+	if name != "" && l.registry.IsLoaded(name) {
+		tool := l.registry.Get(name)
+		tools.Safely(name, func() { tools.Activate(tool) })
+	}
+}
+
+// evictLoop descarga periódicamente las herramientas inactivas y guarda la sesión
+// hasta que se llame a Dispose.
+func (l *AppLayout) evictLoop() {
+	ticker := time.NewTicker(evictInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			fyne.Do(func() {
+				l.evictIdle()
+				l.SaveSession()
+			})
+		case <-l.stopEviction:
+			return
+		}
+	}
+}
+
+// evictIdle descarga las herramientas inactivas, salvo la visible, y vacía los
+// paneles que mostraban su UI para que se reconstruya al volver a ellas.
+func (l *AppLayout) evictIdle() {
+	l.captureState() // El estado de las que se descarguen sigue en la sesión.
+	evicted := l.registry.EvictIdle(idleTimeout, func(name string) bool {
+		_, poppedOut := l.popouts[name]
+		return name == l.active || poppedOut
+	})
+	for _, name := range evicted {
+		for _, cv := range l.categories {
+			if cv.loaded == name {
+				cv.content.Objects = nil
+				cv.content.Refresh()
+				cv.loaded = ""
+			}
+		}
+		delete(l.contexts, name)
+	}
+}
+
+// Dispose guarda la sesión, desactiva la herramienta visible y descarga todas
+// las herramientas. Se llama cuando la ventana se cierra.
+func (l *AppLayout) Dispose() {
+	l.SaveSession()
+	for _, cleanup := range l.cleanups {
+		cleanup()
+	}
+	close(l.stopEviction)
+	l.closePopOuts()
+	l.setActive("")
+	l.registry.DisposeAll()
 }
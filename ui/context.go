@@ -0,0 +1,66 @@
+package ui
+
+import (
+	"log/slog"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+	"github.com/Lec7ral/MultiTool/logging"
+	"github.com/Lec7ral/MultiTool/tools"
+	"github.com/Lec7ral/MultiTool/undo"
+)
+
+// statusBar implementa tools.StatusBar sobre una etiqueta de la barra inferior.
+type statusBar struct {
+	label *widget.Label
+}
+
+func newStatusBar() *statusBar {
+	label := widget.NewLabel("")
+	label.Truncation = fyne.TextTruncateEllipsis
+	return &statusBar{label: label}
+}
+
+// SetStatus puede llamarse desde cualquier goroutine.
+func (s *statusBar) SetStatus(text string) {
+	fyne.Do(func() { s.label.SetText(text) })
+}
+
+// noStatus descarta los mensajes de estado de los contextos que no tienen ventana,
+// como los de la bandeja del sistema.
+type noStatus struct{}
+
+func (noStatus) SetStatus(string) {}
+
+// appNotifier envía las notificaciones a través de la aplicación Fyne.
+type appNotifier struct {
+	app fyne.App
+}
+
+func (n appNotifier) Notify(title, content string) {
+	n.app.SendNotification(fyne.NewNotification(title, content))
+}
+
+// BackgroundContext devuelve un contexto sin ventana para la herramienta name,
+// como el de la bandeja, para el trabajo que no depende de la ventana (la API de
+// automatización, por ejemplo). Sus tareas aparecen en el panel de tareas.
+func BackgroundContext(services *AppServices, name string) *tools.ToolContext {
+	return newToolContext(nil, noStatus{}, services, name)
+}
+
+// newToolContext construye el contexto que recibe la herramienta indicada.
+func newToolContext(w fyne.Window, status tools.StatusBar, services *AppServices, toolName string) *tools.ToolContext {
+	app := fyne.CurrentApp()
+	return &tools.ToolContext{
+		ToolName: toolName,
+		Window:   w,
+		Status:   status,
+		Notifier: appNotifier{app: app},
+		Settings: services.Settings.Section(toolName),
+		Logger:   slog.Default().With(logging.ToolKey, toolName),
+		Jobs:     services.Jobs,
+		Systray:  services.Systray,
+		Undo:     undo.NewStack(undo.DefaultLimit),
+		Exit:     toolExitHooks{hooks: services.Exit, toolName: toolName},
+	}
+}
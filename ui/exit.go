@@ -0,0 +1,54 @@
+package ui
+
+import (
+	"sync"
+
+	"github.com/Lec7ral/MultiTool/tools"
+)
+
+// ExitHooks guarda las funciones que las herramientas registran con
+// ToolContext.Exit. main las ejecuta con Run al terminar la aplicación.
+type ExitHooks struct {
+	mu     sync.Mutex
+	nextID int
+	hooks  map[int]func()
+}
+
+// OnExit registra fn y devuelve la función que la quita.
+func (h *ExitHooks) OnExit(fn func()) (remove func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.hooks == nil {
+		h.hooks = make(map[int]func())
+	}
+	id := h.nextID
+	h.nextID++
+	h.hooks[id] = fn
+	return func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		delete(h.hooks, id)
+	}
+}
+
+// Run ejecuta (y quita) las funciones registradas.
+func (h *ExitHooks) Run() {
+	h.mu.Lock()
+	hooks := h.hooks
+	h.hooks = nil
+	h.mu.Unlock()
+	for _, fn := range hooks {
+		fn()
+	}
+}
+
+// toolExitHooks es el ToolContext.Exit de una herramienta: protege sus funciones
+// contra pánicos, como el resto de su código.
+type toolExitHooks struct {
+	hooks    *ExitHooks
+	toolName string
+}
+
+func (t toolExitHooks) OnExit(fn func()) (remove func()) {
+	return t.hooks.OnExit(func() { tools.Safely(t.toolName, fn) })
+}
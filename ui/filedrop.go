@@ -0,0 +1,140 @@
+package ui
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"github.com/Lec7ral/MultiTool/i18n"
+	"github.com/Lec7ral/MultiTool/tools"
+)
+
+// localPath convierte una URI de archivo en una ruta del sistema de archivos.
+func localPath(u fyne.URI) string {
+	path := u.Path()
+	// En Windows, las URIs de Fyne pueden llevar una barra inicial ("/C:/...").
+	if len(path) > 2 && path[0] == '/' && path[2] == ':' {
+		path = path[1:]
+	}
+	return filepath.FromSlash(path)
+}
+
+// routeDroppedFiles entrega files a la herramienta tool si los acepta (según los
+// tipos de su descriptor) y avisa al usuario, en w, de los archivos que no se han
+// podido entregar. Un pánico al recibirlos muestra su panel de error.
+func (l *AppLayout) routeDroppedFiles(w fyne.Window, status tools.StatusBar, toolName string, tool tools.Tool, files []string) {
+	if len(files) == 0 {
+		return
+	}
+	types := l.acceptedTypes(toolName)
+
+	dropper, ok := tool.(tools.FileDropper)
+	if !ok || len(types) == 0 {
+		dialog.ShowInformation(i18n.T("Files not supported"),
+			i18n.T("%s does not accept dropped files.", i18n.T(toolName)), w)
+		return
+	}
+
+	accepted, rejected := tools.FilterAccepted(types, files)
+	if len(accepted) == 0 {
+		dialog.ShowInformation(i18n.T("Unsupported files"),
+			i18n.T("%s only accepts: %s", i18n.T(toolName), strings.Join(types, ", ")), w)
+		return
+	}
+
+	if err := tools.Safely(toolName, func() { dropper.OnFilesDropped(accepted) }); err != nil {
+		l.toolCrashed(toolName, err)
+		return
+	}
+	if len(rejected) > 0 {
+		names := make([]string, len(rejected))
+		for i, f := range rejected {
+			names[i] = filepath.Base(f)
+		}
+		status.SetStatus(i18n.T("Ignored %d unsupported file(s): %s", len(rejected), strings.Join(names, ", ")))
+	} else {
+		status.SetStatus(i18n.T("%d file(s) added to %s", len(accepted), i18n.T(toolName)))
+	}
+}
+
+// OpenFiles abre cada archivo de paths en la herramienta que lo acepte (la visible
+// si puede; si no, la primera registrada que lo acepte) y cambia a la pestaña de
+// la primera de ellas. Se usa para los archivos de la línea de comandos.
+func (l *AppLayout) OpenFiles(paths []string) {
+	groups := make(map[string][]string)
+	var order, unsupported []string
+	for _, path := range paths {
+		name := l.findDropper(path)
+		if name == "" {
+			unsupported = append(unsupported, filepath.Base(path))
+			continue
+		}
+		if _, ok := groups[name]; !ok {
+			order = append(order, name)
+		}
+		groups[name] = append(groups[name], path)
+	}
+
+	if len(order) > 0 {
+		l.SelectTool(order[0])
+	}
+	summary := make([]string, 0, len(order))
+	for _, name := range order {
+		if l.failed[name] != nil {
+			continue // Su panel de error ya lo explica.
+		}
+		tool, err := l.registry.Load(name)
+		if err != nil {
+			l.failed[name] = err
+			l.refreshTool(name)
+			dialog.ShowError(err, l.window)
+			continue
+		}
+		dropper, ok := tool.(tools.FileDropper)
+		if !ok {
+			dialog.ShowError(errors.New(i18n.T("%s declares accepted file types but does not accept files.", i18n.T(name))), l.window)
+			continue
+		}
+		if err := tools.Safely(name, func() { dropper.OnFilesDropped(groups[name]) }); err != nil {
+			l.toolCrashed(name, err) // Su panel de error lo explica.
+			continue
+		}
+		summary = append(summary, i18n.T("%d file(s) added to %s", len(groups[name]), i18n.T(name)))
+	}
+	if len(summary) > 0 {
+		l.status.SetStatus(strings.Join(summary, "; "))
+	}
+
+	if len(unsupported) > 0 {
+		dialog.ShowInformation(i18n.T("Unsupported files"),
+			i18n.T("No tool can open these files:")+"\n"+strings.Join(unsupported, "\n"), l.window)
+	}
+}
+
+// findDropper devuelve el nombre de la herramienta que acepta path, dando
+// preferencia a la herramienta visible, o "" si ninguna lo acepta. Solo consulta
+// los descriptores, así que no construye ninguna herramienta. Las herramientas
+// que han fallado no reciben archivos.
+func (l *AppLayout) findDropper(path string) string {
+	accepts := func(d tools.ToolDescriptor) bool {
+		return l.failed[d.Name] == nil && tools.Accepts(d.AcceptedTypes, path)
+	}
+
+	if d, ok := l.registry.Descriptor(l.active); ok && accepts(d) {
+		return d.Name
+	}
+	for _, d := range l.registry.GetAllDescriptors() {
+		if accepts(d) {
+			return d.Name
+		}
+	}
+	return ""
+}
+
+// acceptedTypes devuelve los tipos de archivo que acepta la herramienta name.
+func (l *AppLayout) acceptedTypes(name string) []string {
+	d, _ := l.registry.Descriptor(name)
+	return d.AcceptedTypes
+}
@@ -0,0 +1,68 @@
+package ui
+
+import (
+	"math"
+
+	"fyne.io/fyne/v2"
+	"github.com/Lec7ral/MultiTool/settings"
+)
+
+// windowSection es la sección de ajustes (sin esquema, no aparece en la pantalla de
+// ajustes) donde se recuerda el estado de la ventana principal entre sesiones.
+const windowSection = "window"
+
+// Claves de windowSection.
+const (
+	keyWidth    = "width"
+	keyHeight   = "height"
+	keyX        = "x"
+	keyY        = "y"
+	keyCategory = "category"
+	keyToolIn   = "tool." // + nombre de la categoría
+)
+
+// SavedWindowSize devuelve el tamaño guardado de la ventana principal, o def si no hay ninguno.
+func SavedWindowSize(store *settings.Store, def fyne.Size) fyne.Size {
+	section := store.Section(windowSection)
+	width := section.Float(keyWidth, 0)
+	height := section.Float(keyHeight, 0)
+	if width <= 0 || height <= 0 {
+		return def
+	}
+	return fyne.NewSize(float32(width), float32(height))
+}
+
+// RestoreWindowPosition mueve w a la posición guardada. Debe llamarse después de
+// w.Show(), cuando ya existe la ventana nativa. Solo tiene efecto donde Fyne
+// permite acceder a la ventana nativa (Windows). Si la posición ya no cae en
+// ningún monitor (se desconectó o cambió la resolución), centra la ventana.
+func RestoreWindowPosition(w fyne.Window, store *settings.Store) {
+	section := store.Section(windowSection)
+	// Las coordenadas pueden ser negativas (monitor a la izquierda del principal),
+	// así que usamos un valor imposible para "sin posición guardada".
+	const unset = math.MaxInt32
+	x := section.Float(keyX, unset)
+	y := section.Float(keyY, unset)
+	if x == unset || y == unset {
+		return
+	}
+	if !positionVisible(int(x), int(y)) {
+		w.CenterOnScreen()
+		return
+	}
+	setWindowPosition(w, int(x), int(y))
+}
+
+// SaveWindowGeometry guarda el tamaño y la posición actuales de w. Debe llamarse
+// mientras la ventana nativa existe: antes de cerrarla (desde SetCloseIntercept)
+// o de salir de la aplicación (desde la bandeja).
+func SaveWindowGeometry(w fyne.Window, store *settings.Store) {
+	section := store.Section(windowSection)
+	size := w.Canvas().Size()
+	section.SetFloat(keyWidth, float64(size.Width))
+	section.SetFloat(keyHeight, float64(size.Height))
+	if x, y, ok := windowPosition(w); ok {
+		section.SetFloat(keyX, float64(x))
+		section.SetFloat(keyY, float64(y))
+	}
+}
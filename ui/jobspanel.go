@@ -0,0 +1,132 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/Lec7ral/MultiTool/i18n"
+	"github.com/Lec7ral/MultiTool/jobs"
+)
+
+// newJobsButton crea el botón de la barra de estado que abre el panel de tareas.
+// Su texto muestra cuántas tareas están en ejecución. La función devuelta anula la
+// suscripción al Manager y debe llamarse al cerrar la ventana.
+func newJobsButton(w fyne.Window, manager *jobs.Manager) (*widget.Button, func()) {
+	button := widget.NewButtonWithIcon(i18n.T("Jobs"), theme.ListIcon(), func() {
+		showJobsPanel(w, manager)
+	})
+	update := func() {
+		if n := manager.Running(); n > 0 {
+			button.SetText(i18n.T("Jobs (%d running)", n))
+		} else {
+			button.SetText(i18n.T("Jobs"))
+		}
+	}
+	update()
+	remove := manager.OnChange(func() { fyne.Do(update) })
+	return button, remove
+}
+
+// showJobsPanel muestra la lista de tareas en curso y terminadas, con su progreso,
+// un botón para cancelarlas y el log de la tarea seleccionada.
+func showJobsPanel(w fyne.Window, manager *jobs.Manager) {
+	infos := manager.Jobs()
+	selectedID := 0
+
+	logView := widget.NewLabel(i18n.T("Select a job to see its log."))
+	logView.Wrapping = fyne.TextWrapWord
+	logView.TextStyle = fyne.TextStyle{Monospace: true}
+
+	list := widget.NewList(
+		func() int { return len(infos) },
+		func() fyne.CanvasObject {
+			title := widget.NewLabel("template")
+			title.Truncation = fyne.TextTruncateEllipsis
+			cancel := widget.NewButtonWithIcon("", theme.CancelIcon(), nil)
+			return container.NewBorder(nil, nil, nil, cancel, container.NewVBox(title, widget.NewProgressBar()))
+		},
+		func(i widget.ListItemID, o fyne.CanvasObject) {
+			info := infos[i]
+			c := o.(*fyne.Container)
+			body := c.Objects[0].(*fyne.Container)
+			cancel := c.Objects[1].(*widget.Button)
+
+			body.Objects[0].(*widget.Label).SetText(fmt.Sprintf("%s: %s — %s", i18n.T(info.Tool), info.Title, jobStateText(info)))
+			progress := body.Objects[1].(*widget.ProgressBar)
+			if info.Progress < 0 {
+				progress.SetValue(0)
+			} else {
+				progress.SetValue(info.Progress)
+			}
+
+			id := info.ID
+			cancel.OnTapped = func() {
+				if j := manager.Get(id); j != nil {
+					j.Cancel()
+				}
+			}
+			if info.State == jobs.Running {
+				cancel.Enable()
+			} else {
+				cancel.Disable()
+			}
+		},
+	)
+
+	showLog := func() {
+		for _, info := range infos {
+			if info.ID == selectedID {
+				if len(info.Logs) == 0 {
+					logView.SetText(i18n.T("(no output)"))
+				} else {
+					logView.SetText(strings.Join(info.Logs, "\n"))
+				}
+				return
+			}
+		}
+	}
+	list.OnSelected = func(id widget.ListItemID) {
+		selectedID = infos[id].ID
+		showLog()
+	}
+
+	refresh := func() {
+		infos = manager.Jobs()
+		list.Refresh()
+		showLog()
+	}
+	remove := manager.OnChange(func() { fyne.Do(refresh) })
+
+	clearBtn := widget.NewButton(i18n.T("Clear finished"), func() {
+		manager.ClearFinished()
+	})
+
+	split := container.NewVSplit(list, container.NewVScroll(logView))
+	split.Offset = 0.6
+	content := container.NewBorder(nil, container.NewHBox(clearBtn), nil, nil, split)
+
+	d := dialog.NewCustom(i18n.T("Jobs"), i18n.T("Close"), content, w)
+	d.SetOnClosed(remove)
+	d.Resize(fyne.NewSize(640, 420))
+	d.Show()
+}
+
+// jobStateText describe el estado de una tarea para la lista.
+func jobStateText(info jobs.Info) string {
+	switch info.State {
+	case jobs.Running:
+		return i18n.T("running since %s", info.Started.Format("15:04:05"))
+	case jobs.Failed:
+		return i18n.T("failed: %s", info.Err.Error())
+	case jobs.Canceled:
+		return i18n.T("canceled after %s", info.Finished.Sub(info.Started).Round(100*time.Millisecond))
+	default:
+		return i18n.T("done in %s", info.Finished.Sub(info.Started).Round(100*time.Millisecond))
+	}
+}
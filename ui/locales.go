@@ -0,0 +1,20 @@
+package ui
+
+import (
+	"embed"
+	"log/slog"
+
+	"github.com/Lec7ral/MultiTool/i18n"
+)
+
+// locales contiene las traducciones de la interfaz. Cada herramienta registra las
+// suyas desde su propio paquete.
+//
+//go:embed locales
+var locales embed.FS
+
+func init() {
+	if err := i18n.AddCatalogFS(locales, "locales"); err != nil {
+		slog.Error("failed to load ui translations", "err", err)
+	}
+}
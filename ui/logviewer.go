@@ -0,0 +1,154 @@
+package ui
+
+import (
+	"log/slog"
+	"net/url"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/Lec7ral/MultiTool/i18n"
+	"github.com/Lec7ral/MultiTool/logging"
+	"github.com/Lec7ral/MultiTool/settings"
+	"github.com/Lec7ral/MultiTool/tools"
+)
+
+// logWindow es la ventana del visor de logs, si está abierta.
+var logWindow fyne.Window
+
+// Niveles que ofrecen el ajuste de nivel de log y el filtro del visor.
+var (
+	logLevels      = []string{"debug", "info", "warn", "error"}
+	logLevelLabels = map[string]string{"debug": "Debug", "info": "Info", "warn": "Warning", "error": "Error"}
+)
+
+// ApplyLogLevel fija el nivel de log elegido en los ajustes y lo actualiza cuando
+// el ajuste cambia.
+func ApplyLogLevel(store *settings.Store) {
+	section := store.Section(settings.AppSection)
+	apply := func() {
+		logging.SetLevel(logging.ParseLevel(section.String(SettingLogLevel, "info")))
+	}
+	apply()
+	store.OnChange(func(sectionName, key string) {
+		if sectionName == settings.AppSection && key == SettingLogLevel {
+			apply()
+		}
+	})
+}
+
+// showLogViewer abre el visor de logs, o lo trae al frente si ya está abierto.
+// Muestra los últimos registros de la aplicación, con filtros por herramienta y
+// por nivel, y permite copiar un informe de diagnóstico.
+func showLogViewer() {
+	if logWindow != nil {
+		logWindow.Show()
+		logWindow.RequestFocus()
+		return
+	}
+
+	app := fyne.CurrentApp()
+	w := app.NewWindow(i18n.T("Logs"))
+	logWindow = w
+	w.Resize(fyne.NewSize(900, 500))
+
+	var shown []logging.Entry
+	toolFilter := ""    // "" = todas; appTool = sin herramienta.
+	const appTool = "-" // Valor del filtro para los registros de la aplicación.
+	minLevel := slog.LevelDebug
+
+	list := widget.NewList(
+		func() int { return len(shown) },
+		func() fyne.CanvasObject {
+			label := widget.NewLabel("template")
+			label.TextStyle = fyne.TextStyle{Monospace: true}
+			label.Truncation = fyne.TextTruncateEllipsis
+			return label
+		},
+		func(i widget.ListItemID, o fyne.CanvasObject) {
+			label := o.(*widget.Label)
+			switch e := shown[i]; {
+			case e.Level >= slog.LevelError:
+				label.Importance = widget.DangerImportance
+			case e.Level >= slog.LevelWarn:
+				label.Importance = widget.WarningImportance
+			default:
+				label.Importance = widget.MediumImportance
+			}
+			label.SetText(shown[i].String())
+		},
+	)
+
+	refresh := func() {
+		shown = shown[:0]
+		for _, e := range logging.Entries() {
+			if e.Level < minLevel {
+				continue
+			}
+			if toolFilter == appTool && e.Tool != "" || toolFilter != "" && toolFilter != appTool && e.Tool != toolFilter {
+				continue
+			}
+			shown = append(shown, e)
+		}
+		list.Refresh()
+		list.ScrollToBottom()
+	}
+
+	// --- Filtros ---
+	toolNames := map[string]string{i18n.T("All tools"): "", i18n.T("Application"): appTool}
+	toolOptions := []string{i18n.T("All tools"), i18n.T("Application")}
+	for _, d := range tools.Descriptors() {
+		toolNames[i18n.T(d.Name)] = d.Name
+		toolOptions = append(toolOptions, i18n.T(d.Name))
+	}
+	toolSelect := widget.NewSelect(toolOptions, func(s string) {
+		toolFilter = toolNames[s]
+		refresh()
+	})
+
+	levelNames := make(map[string]slog.Level, len(logLevels))
+	levelOptions := make([]string, len(logLevels))
+	for i, l := range logLevels {
+		levelOptions[i] = i18n.T(logLevelLabels[l])
+		levelNames[levelOptions[i]] = logging.ParseLevel(l)
+	}
+	levelSelect := widget.NewSelect(levelOptions, func(s string) {
+		minLevel = levelNames[s]
+		refresh()
+	})
+
+	// --- Acciones ---
+	info := widget.NewLabel("")
+	copyBtn := widget.NewButtonWithIcon(i18n.T("Copy diagnostics"), theme.ContentCopyIcon(), func() {
+		app.Clipboard().SetContent(logging.Diagnostics("MultiTool", AppVersion))
+		info.SetText(i18n.T("Diagnostics copied to the clipboard."))
+	})
+	folderBtn := widget.NewButtonWithIcon(i18n.T("Open log folder"), theme.FolderOpenIcon(), func() {
+		u, err := url.Parse(storage.NewFileURI(logging.Dir()).String())
+		if err == nil {
+			err = app.OpenURL(u)
+		}
+		if err != nil {
+			slog.Error("failed to open log folder", "path", logging.Dir(), "err", err)
+		}
+	})
+
+	filters := container.NewHBox(
+		widget.NewLabel(i18n.T("Tool")), toolSelect,
+		widget.NewLabel(i18n.T("Minimum level")), levelSelect,
+	)
+	actions := container.NewBorder(nil, nil, nil, container.NewHBox(folderBtn, copyBtn), info)
+	w.SetContent(container.NewBorder(filters, actions, nil, nil, list))
+
+	toolSelect.SetSelectedIndex(0)
+	levelSelect.SetSelectedIndex(0)
+
+	remove := logging.OnChange(func() { fyne.Do(refresh) })
+	w.SetOnClosed(func() {
+		remove()
+		logWindow = nil
+	})
+	w.Show()
+}
@@ -0,0 +1,309 @@
+package ui
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/Lec7ral/MultiTool/i18n"
+	"github.com/Lec7ral/MultiTool/tools"
+	"github.com/Lec7ral/MultiTool/workflow"
+)
+
+// paletteMaxResults limita la lista para que siga siendo manejable.
+const paletteMaxResults = 50
+
+// paletteItem es una entrada de la paleta: una herramienta o una de sus acciones.
+type paletteItem struct {
+	title  string
+	detail string
+	icon   fyne.Resource
+	tool   string // Herramienta a la que se salta ("" en las entradas de la aplicación).
+	run    func() // Acción que se ejecuta después de mostrar la herramienta (opcional).
+
+	// Textos en los que se busca, de más a menos importante.
+	fields []string
+}
+
+// paletteItems devuelve las entradas de la aplicación, una por herramienta y una
+// por cada acción que declaren en tools.ToolDescriptor.Actions.
+func (l *AppLayout) paletteItems() []paletteItem {
+	items := []paletteItem{
+		{title: i18n.T("Keyboard shortcuts"), detail: i18n.T("Application"), icon: theme.HelpIcon(), run: l.ShowShortcuts,
+			fields: []string{i18n.T("Keyboard shortcuts"), i18n.T("Application")}},
+		{title: i18n.T("Settings"), detail: i18n.T("Application"), icon: theme.SettingsIcon(), run: l.showSettings,
+			fields: []string{i18n.T("Settings"), i18n.T("Application")}},
+		{title: i18n.T("Save workspace..."), detail: i18n.T("Application"), icon: theme.DocumentSaveIcon(), run: l.SaveWorkspace,
+			fields: []string{i18n.T("Save workspace..."), i18n.T("Application")}},
+		{title: i18n.T("Open workspace..."), detail: i18n.T("Application"), icon: theme.FolderOpenIcon(), run: l.OpenWorkspace,
+			fields: []string{i18n.T("Open workspace..."), i18n.T("Application")}},
+		{title: i18n.T("Workflows"), detail: i18n.T("Application"), icon: theme.MediaPlayIcon(), run: func() { showWorkflows(l.services, "") },
+			fields: []string{i18n.T("Workflows"), i18n.T("Application")}},
+	}
+	workflows, _ := workflow.Load()
+	for _, wf := range workflows {
+		title := i18n.T("Run workflow %s", wf.Name)
+		items = append(items, paletteItem{
+			title:  title,
+			detail: wf.Describe(),
+			icon:   theme.MediaPlayIcon(),
+			run:    func() { runWorkflow(l.window, l.services, wf) },
+			fields: []string{title, wf.Describe(), i18n.T("Workflows")},
+		})
+	}
+	for _, d := range l.registry.GetAllDescriptors() {
+		name, category, description := i18n.T(d.Name), i18n.T(d.Category), i18n.T(d.Description)
+		items = append(items, paletteItem{
+			title:  name,
+			detail: category + " · " + description,
+			icon:   d.Icon,
+			tool:   d.Name,
+			fields: []string{name, category, description},
+		})
+
+		if l.failed[d.Name] != nil {
+			continue
+		}
+		toolName := d.Name
+		items = append(items, paletteItem{
+			title:  i18n.T("Open %s in a new window", name),
+			detail: category,
+			icon:   d.Icon,
+			tool:   d.Name,
+			run:    func() { l.PopOut(toolName) },
+			fields: []string{i18n.T("Open %s in a new window", name), category},
+		})
+
+		// Las acciones se declaran en el descriptor: la herramienta solo se
+		// construye (con SelectTool) al elegir una.
+		if d.Actions == nil {
+			continue
+		}
+		var actions []tools.Action
+		tools.Safely(d.Name, func() { actions = d.Actions() })
+		for _, a := range actions {
+			id := a.ID
+			detail := name
+			if a.Description != "" {
+				detail += " · " + a.Description
+			}
+			items = append(items, paletteItem{
+				title:  a.Title,
+				detail: detail,
+				icon:   d.Icon,
+				tool:   d.Name,
+				run:    l.guard(d.Name, func() { l.runAction(toolName, id) }),
+				fields: []string{a.Title, name, a.Description, category},
+			})
+		}
+	}
+	return items
+}
+
+// runAction ejecuta la acción id de la herramienta name, que la paleta ya ha
+// construido y mostrado.
+func (l *AppLayout) runAction(name, id string) {
+	tool, ok := l.registry.Loaded(name)
+	if !ok {
+		return
+	}
+	if handler, ok := tool.(tools.ActionHandler); ok {
+		handler.RunAction(id)
+	}
+}
+
+// filterPalette devuelve las entradas que coinciden con query, de mejor a peor.
+// Sin query, las devuelve en su orden original.
+func filterPalette(items []paletteItem, query string) []paletteItem {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return items
+	}
+
+	type scored struct {
+		item  paletteItem
+		score int
+	}
+	var matches []scored
+	for _, item := range items {
+		best, matched := 0, false
+		for i, field := range item.fields {
+			// Un campo menos importante puntúa menos a igual coincidencia.
+			if s, ok := fuzzyScore(query, field); ok && (!matched || s-10*i > best) {
+				best, matched = s-10*i, true
+			}
+		}
+		if matched {
+			matches = append(matches, scored{item, best})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].score > matches[j].score })
+
+	result := make([]paletteItem, 0, len(matches))
+	for _, m := range matches {
+		result = append(result, m.item)
+	}
+	return result
+}
+
+// fuzzyScore indica si todas las letras de query aparecen en text en el mismo
+// orden (sin distinguir mayúsculas) y puntúa la coincidencia: cuentan más las
+// letras seguidas y las que empiezan una palabra.
+func fuzzyScore(query, text string) (int, bool) {
+	q := []rune(strings.ToLower(query))
+	t := []rune(strings.ToLower(text))
+	if len(q) == 0 {
+		return 0, true
+	}
+
+	score, qi, prev := 0, 0, -2
+	for ti := 0; ti < len(t) && qi < len(q); ti++ {
+		if t[ti] != q[qi] {
+			continue
+		}
+		switch {
+		case ti == prev+1:
+			score += 5 // Letras seguidas.
+		case ti == 0 || !unicode.IsLetter(t[ti-1]) && !unicode.IsDigit(t[ti-1]):
+			score += 4 // Principio de palabra.
+		default:
+			score++
+		}
+		if ti == 0 {
+			score += 3
+		}
+		prev = ti
+		qi++
+	}
+	if qi < len(q) {
+		return 0, false
+	}
+	// A igual coincidencia, mejor el texto más corto.
+	return score*10 - len(t)/4, true
+}
+
+// paletteEntry es el campo de búsqueda de la paleta: las flechas mueven la
+// selección de la lista y Escape cierra la paleta.
+type paletteEntry struct {
+	widget.Entry
+	onMove   func(delta int)
+	onEscape func()
+}
+
+func newPaletteEntry() *paletteEntry {
+	e := &paletteEntry{}
+	e.ExtendBaseWidget(e)
+	return e
+}
+
+func (e *paletteEntry) TypedKey(key *fyne.KeyEvent) {
+	switch key.Name {
+	case fyne.KeyUp:
+		e.onMove(-1)
+	case fyne.KeyDown:
+		e.onMove(1)
+	case fyne.KeyEscape:
+		e.onEscape()
+	default:
+		e.Entry.TypedKey(key)
+	}
+}
+
+// ShowPalette muestra la paleta de comandos: busca herramientas por nombre,
+// descripción y categoría, y las acciones que aportan, y salta a la elegida.
+func (l *AppLayout) ShowPalette() {
+	all := l.paletteItems()
+	results := all
+	selected := 0
+
+	entry := newPaletteEntry()
+	entry.SetPlaceHolder(i18n.T("Search tools and actions..."))
+
+	list := widget.NewList(
+		func() int { return min(len(results), paletteMaxResults) },
+		func() fyne.CanvasObject {
+			title := widget.NewLabelWithStyle("template", fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+			detail := widget.NewLabel("template")
+			detail.Truncation = fyne.TextTruncateEllipsis
+			title.Truncation = fyne.TextTruncateEllipsis
+			return container.NewBorder(nil, nil, widget.NewIcon(nil), nil, container.NewVBox(title, detail))
+		},
+		func(i widget.ListItemID, o fyne.CanvasObject) {
+			c := o.(*fyne.Container)
+			texts := c.Objects[0].(*fyne.Container)
+			texts.Objects[0].(*widget.Label).SetText(results[i].title)
+			texts.Objects[1].(*widget.Label).SetText(results[i].detail)
+			icon := results[i].icon
+			if icon == nil {
+				icon = theme.ListIcon()
+			}
+			c.Objects[1].(*widget.Icon).SetResource(icon)
+		},
+	)
+
+	var popup *widget.PopUp
+	choose := func(i int) {
+		if i < 0 || i >= len(results) {
+			return
+		}
+		item := results[i]
+		popup.Hide()
+		if item.tool == "" {
+			item.run()
+		} else if l.SelectTool(item.tool) && item.run != nil {
+			item.run()
+		}
+	}
+
+	// Las selecciones hechas desde el teclado no abren la entrada; un clic sí.
+	fromKeyboard := false
+	selectRow := func(i int) {
+		fromKeyboard = true
+		list.Select(i)
+		fromKeyboard = false
+	}
+	list.OnSelected = func(id widget.ListItemID) {
+		selected = id
+		if !fromKeyboard {
+			choose(id)
+		}
+	}
+
+	entry.OnChanged = func(query string) {
+		results = filterPalette(all, query)
+		selected = 0
+		list.Refresh()
+		if len(results) > 0 {
+			selectRow(0)
+			list.ScrollToTop()
+		}
+	}
+	entry.OnSubmitted = func(string) { choose(selected) }
+	entry.onMove = func(delta int) {
+		n := min(len(results), paletteMaxResults)
+		if n == 0 {
+			return
+		}
+		selected = (selected + delta + n) % n
+		selectRow(selected)
+		list.ScrollTo(selected)
+	}
+	entry.onEscape = func() { popup.Hide() }
+
+	hint := widget.NewLabelWithStyle(i18n.T("↑↓ to move · Enter to open · Esc to close"), fyne.TextAlignTrailing, fyne.TextStyle{Italic: true})
+	openBtn := widget.NewButtonWithIcon(i18n.T("Open"), theme.NavigateNextIcon(), func() { choose(selected) })
+	content := container.NewBorder(entry, container.NewBorder(nil, nil, nil, openBtn, hint), nil, nil, list)
+
+	popup = widget.NewModalPopUp(content, l.window.Canvas())
+	size := l.window.Canvas().Size()
+	popup.Resize(fyne.NewSize(min(640, size.Width*0.9), min(420, size.Height*0.8)))
+	popup.Show()
+	if len(results) > 0 {
+		selectRow(0)
+	}
+	l.window.Canvas().Focus(entry)
+}
@@ -0,0 +1,118 @@
+package ui
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/Lec7ral/MultiTool/i18n"
+	"github.com/Lec7ral/MultiTool/tools"
+)
+
+// Tamaño mínimo con el que se abre la ventana de una herramienta separada.
+var popOutMinSize = fyne.NewSize(480, 360)
+
+// popOutWindow es la ventana propia de una herramienta.
+type popOutWindow struct {
+	window fyne.Window
+	tool   tools.Tool
+	status *statusBar
+	keys   *shortcutSet
+}
+
+// PopOut muestra la herramienta name en su propia ventana. La instancia es la
+// misma que la de la pestaña: su UI se vuelve a construir con un contexto cuya
+// ventana y barra de estado son las nuevas, y la pestaña muestra un aviso hasta
+// que la ventana se cierra y la herramienta vuelve a ella.
+func (l *AppLayout) PopOut(name string) {
+	if p, ok := l.popouts[name]; ok {
+		p.window.Show()
+		p.window.RequestFocus()
+		return
+	}
+	if err := l.failed[name]; err != nil {
+		return
+	}
+	tool, err := l.registry.Load(name)
+	if err != nil {
+		dialog.ShowError(err, l.window)
+		return
+	}
+
+	w := fyne.CurrentApp().NewWindow(i18n.T(name))
+	status := newStatusBar()
+	ctx := newToolContext(w, status, l.services, name)
+	ctx.Undo = l.contextFor(name).Undo // El historial es el de la herramienta, no el de la ventana.
+	ctx.OnPanic = l.crashHandler(name)
+
+	var content fyne.CanvasObject
+	if err := tools.Safely(name, func() { content = tool.GetUI(ctx) }); err != nil {
+		w.Close()
+		l.toolCrashed(name, err)
+		return
+	}
+
+	p := &popOutWindow{window: w, tool: tool, status: status, keys: &shortcutSet{canvas: w.Canvas()}}
+	l.popouts[name] = p
+	w.SetContent(container.NewBorder(nil, status.label, nil, nil, content))
+	w.SetOnDropped(func(_ fyne.Position, uris []fyne.URI) {
+		paths := make([]string, 0, len(uris))
+		for _, u := range uris {
+			paths = append(paths, localPath(u))
+		}
+		l.routeDroppedFiles(w, status, name, tool, paths)
+	})
+	l.installPopOutShortcuts(p, name)
+	w.SetOnClosed(func() {
+		delete(l.popouts, name)
+		l.refreshTool(name)
+	})
+
+	size := content.MinSize().Max(popOutMinSize)
+	w.Resize(size)
+	w.Show()
+	l.refreshTool(name)
+}
+
+// popOutPanel es lo que muestra la pestaña de una herramienta que está en su
+// propia ventana.
+func (l *AppLayout) popOutPanel(name string) fyne.CanvasObject {
+	show := widget.NewButtonWithIcon(i18n.T("Show window"), theme.ViewFullScreenIcon(), func() {
+		l.PopOut(name)
+	})
+	back := widget.NewButtonWithIcon(i18n.T("Return to tab"), theme.ViewRestoreIcon(), func() {
+		if p, ok := l.popouts[name]; ok {
+			p.window.Close()
+		}
+	})
+	back.Importance = widget.HighImportance
+
+	return container.NewCenter(container.NewVBox(
+		widget.NewLabelWithStyle(i18n.T("%s is open in its own window.", i18n.T(name)), fyne.TextAlignCenter, fyne.TextStyle{Bold: true}),
+		container.NewCenter(container.NewHBox(show, back)),
+	))
+}
+
+// refreshTool vuelve a construir el contenido de las pestañas que muestran la
+// herramienta name (su UI, el aviso de ventana separada o el panel de error).
+func (l *AppLayout) refreshTool(name string) {
+	for _, cv := range l.categories {
+		if cv.loaded == name {
+			l.loadInto(cv, name)
+		}
+	}
+	if name == l.active {
+		l.installToolShortcuts()
+	}
+}
+
+// closePopOuts cierra las ventanas separadas sin devolver las herramientas a
+// sus pestañas. Se usa al cerrar la ventana principal.
+func (l *AppLayout) closePopOuts() {
+	for name, p := range l.popouts {
+		p.window.SetOnClosed(nil)
+		p.window.Close()
+		delete(l.popouts, name)
+	}
+}
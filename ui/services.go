@@ -0,0 +1,50 @@
+package ui
+
+import (
+	"log/slog"
+
+	"github.com/Lec7ral/MultiTool/automation"
+	"github.com/Lec7ral/MultiTool/config"
+	"github.com/Lec7ral/MultiTool/jobs"
+	"github.com/Lec7ral/MultiTool/settings"
+	"github.com/Lec7ral/MultiTool/tools"
+)
+
+// AppServices son los servicios de la aplicación compartidos por todas las
+// ventanas y por la bandeja del sistema. Sobreviven al cierre de la ventana.
+type AppServices struct {
+	Jobs     *jobs.Manager
+	Settings *settings.Store
+	Systray  tools.SystrayMenu // Lo sustituye InstallSystray; hasta entonces no hace nada.
+	Exit     *ExitHooks        // main las ejecuta al terminar la aplicación.
+
+	// SettingsError es el error al cargar settings.json, si lo hubo. La primera
+	// ventana lo muestra y lo borra.
+	SettingsError error
+}
+
+// NewAppServices crea los servicios de la aplicación y registra los esquemas de
+// ajustes de la aplicación y de las herramientas.
+func NewAppServices() *AppServices {
+	store, err := settings.Open(config.Path("settings.json"))
+	if err != nil {
+		// Seguimos con lo que se haya podido cargar; Open no sobrescribe el archivo.
+		slog.Error("failed to load settings", "err", err)
+	}
+	store.Register(appSettingsSchema())
+	store.Register(automation.Schema())
+	for _, d := range tools.Descriptors() {
+		if len(d.Settings) > 0 {
+			store.Register(settings.Schema{Section: d.Name, Title: d.Name, Version: 1, Fields: d.Settings})
+		}
+	}
+
+	return &AppServices{
+		Jobs:     jobs.NewManager(),
+		Settings: store,
+		Systray:  noSystray{},
+		Exit:     &ExitHooks{},
+
+		SettingsError: err,
+	}
+}
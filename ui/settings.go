@@ -0,0 +1,190 @@
+package ui
+
+import (
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+	"github.com/Lec7ral/MultiTool/i18n"
+	"github.com/Lec7ral/MultiTool/settings"
+)
+
+// Claves de la sección "app" de los ajustes.
+const (
+	SettingTheme          = "theme"
+	SettingLanguage       = "language"
+	SettingStartMinimized = "startMinimized"
+	SettingLogLevel       = "logLevel"
+	SettingRestoreSession = "restoreSession"
+)
+
+// LanguageSystem es el valor de SettingLanguage que usa el idioma del sistema.
+const LanguageSystem = "system"
+
+// languageNames son los nombres de los idiomas en su propio idioma.
+var languageNames = map[string]string{
+	"en": "English",
+	"es": "Español",
+}
+
+// languageOptions devuelve las opciones del ajuste de idioma: el del sistema y
+// todos los que tienen catálogo.
+func languageOptions() ([]string, map[string]string) {
+	options := []string{LanguageSystem}
+	labels := map[string]string{LanguageSystem: "System language"}
+	for _, lang := range i18n.Languages() {
+		options = append(options, lang)
+		if name, ok := languageNames[lang]; ok {
+			labels[lang] = name
+		}
+	}
+	return options, labels
+}
+
+// ApplyLanguage elige el idioma de la interfaz según los ajustes. Los textos ya
+// mostrados no cambian, así que hay que llamarla antes de crear las ventanas.
+func ApplyLanguage(store *settings.Store) {
+	lang := store.Section(settings.AppSection).String(SettingLanguage, LanguageSystem)
+	if lang == LanguageSystem {
+		lang = ""
+	}
+	i18n.SetLanguage(lang)
+}
+
+// appSettingsSchema devuelve el esquema de los ajustes generales. Se construye en
+// cada llamada porque la lista de temas depende de los archivos de la carpeta de temas.
+func appSettingsSchema() settings.Schema {
+	languages, languageLabels := languageOptions()
+	return settings.Schema{
+		Section: settings.AppSection,
+		Title:   "General",
+		Version: 1,
+		Fields: []settings.Field{
+			{Key: SettingTheme, Label: "Theme", Kind: settings.Choice, Default: ThemeDark, Options: ThemeNames(),
+				Description: "Custom themes (JSON or TOML) go in the themes folder of the configuration directory."},
+			{Key: SettingLanguage, Label: "Language", Kind: settings.Choice, Default: LanguageSystem,
+				Options: languages, OptionLabels: languageLabels,
+				Description: "Takes effect the next time MultiTool starts."},
+			{Key: SettingStartMinimized, Label: "Start minimized to the system tray", Kind: settings.Bool, Default: false},
+			{Key: SettingRestoreSession, Label: "Restore the last session on startup", Kind: settings.Bool, Default: true,
+				Description: "Tools get back their work, such as the PDF Merger's file list."},
+			{Key: SettingLogLevel, Label: "Log level", Kind: settings.Choice, Default: "info",
+				Options: logLevels, OptionLabels: logLevelLabels},
+		},
+	}
+}
+
+// showSettings muestra la pantalla de ajustes, con una pestaña por sección. Los
+// cambios solo se guardan al pulsar "Save".
+func showSettings(w fyne.Window, store *settings.Store) {
+	var apply []func()
+	tabs := container.NewAppTabs()
+
+	for _, schema := range store.Schemas() {
+		section := store.Section(schema.Section)
+		form := widget.NewForm()
+		for _, field := range schema.Fields {
+			item, save := settingWidget(w, section, field)
+			item.HintText = i18n.T(field.Description)
+			form.AppendItem(item)
+			apply = append(apply, save)
+		}
+		tabs.Append(container.NewTabItem(i18n.T(schema.Title), container.NewVScroll(form)))
+	}
+	tabs.SetTabLocation(container.TabLocationLeading)
+
+	d := dialog.NewCustomConfirm(i18n.T("Settings"), i18n.T("Save"), i18n.T("Cancel"), tabs, func(ok bool) {
+		if !ok {
+			return
+		}
+		for _, save := range apply {
+			save()
+		}
+	}, w)
+	d.Resize(fyne.NewSize(640, 400))
+	d.Show()
+}
+
+// settingWidget crea el widget de un campo y la función que guarda su valor.
+func settingWidget(w fyne.Window, section *settings.Section, field settings.Field) (*widget.FormItem, func()) {
+	label := i18n.T(field.Label)
+	defString, _ := field.Default.(string)
+	defBool, _ := field.Default.(bool)
+
+	switch field.Kind {
+	case settings.Bool:
+		check := widget.NewCheck("", nil)
+		check.SetChecked(section.Bool(field.Key, defBool))
+		return widget.NewFormItem(label, check), func() { section.SetBool(field.Key, check.Checked) }
+
+	case settings.Choice:
+		// El Select muestra los textos traducidos; se guarda la opción original.
+		shown := make([]string, len(field.Options))
+		values := make(map[string]string, len(field.Options))
+		for i, option := range field.Options {
+			text := option
+			if l, ok := field.OptionLabels[option]; ok {
+				text = l
+			}
+			shown[i] = i18n.T(text)
+			values[shown[i]] = option
+		}
+		sel := widget.NewSelect(shown, nil)
+		current := section.String(field.Key, defString)
+		for text, option := range values {
+			if option == current {
+				sel.SetSelected(text)
+			}
+		}
+		return widget.NewFormItem(label, sel), func() {
+			if option, ok := values[sel.Selected]; ok {
+				section.SetString(field.Key, option)
+			}
+		}
+
+	case settings.Folder:
+		entry := widget.NewEntry()
+		entry.SetText(section.String(field.Key, defString))
+		browse := widget.NewButton(i18n.T("Browse..."), func() {
+			folderDialog := dialog.NewFolderOpen(func(uri fyne.ListableURI, err error) {
+				if err == nil && uri != nil {
+					entry.SetText(localPath(uri))
+				}
+			}, w)
+			if current, err := storage.ListerForURI(storage.NewFileURI(entry.Text)); err == nil && entry.Text != "" {
+				folderDialog.SetLocation(current)
+			}
+			folderDialog.Show()
+		})
+		return widget.NewFormItem(label, container.NewBorder(nil, nil, nil, browse, entry)),
+			func() { section.SetString(field.Key, entry.Text) }
+
+	case settings.Shortcut:
+		// Se guarda normalizada ("ctrl+o" pasa a "Ctrl+O"); vacía desactiva el atajo.
+		entry := widget.NewEntry()
+		entry.SetPlaceHolder(i18n.T("e.g. Ctrl+Shift+O"))
+		entry.SetText(section.String(field.Key, defString))
+		entry.Validator = func(s string) error {
+			if strings.TrimSpace(s) == "" {
+				return nil
+			}
+			_, err := parseKeys(s)
+			return err
+		}
+		return widget.NewFormItem(label, entry), func() {
+			if strings.TrimSpace(entry.Text) == "" {
+				section.SetString(field.Key, "")
+			} else if shortcut, err := parseKeys(entry.Text); err == nil {
+				section.SetString(field.Key, formatKeys(shortcut))
+			}
+		}
+
+	default:
+		entry := widget.NewEntry()
+		entry.SetText(section.String(field.Key, defString))
+		return widget.NewFormItem(label, entry), func() { section.SetString(field.Key, entry.Text) }
+	}
+}
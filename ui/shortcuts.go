@@ -0,0 +1,412 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/widget"
+	"github.com/Lec7ral/MultiTool/i18n"
+	"github.com/Lec7ral/MultiTool/settings"
+	"github.com/Lec7ral/MultiTool/tools"
+)
+
+// shortcutsSection es la sección de los ajustes con las teclas que el usuario ha
+// cambiado. La clave es el id del atajo; un valor vacío lo desactiva.
+const shortcutsSection = "shortcuts"
+
+// toolActionKeys son las teclas por defecto de los atajos que la aplicación
+// dirige a la herramienta visible.
+var toolActionKeys = map[string]string{
+	tools.ShortcutOpen:    "Ctrl+O",
+	tools.ShortcutPrimary: "Ctrl+Enter",
+}
+
+// binding es un atajo de teclado con su acción.
+type binding struct {
+	id    string // Clave en la sección de atajos de los ajustes.
+	title string // Ya traducido.
+	keys  string // Combinación por defecto.
+	run   func() // Nil si solo se lista: los de toolActionKeys o los de una herramienta sin cargar.
+}
+
+// appBindings devuelve los atajos de la aplicación.
+func (l *AppLayout) appBindings() []binding {
+	bindings := []binding{
+		{id: "palette", title: i18n.T("Command palette"), keys: "Ctrl+K", run: l.ShowPalette},
+		{id: "shortcuts", title: i18n.T("Keyboard shortcuts"), keys: "Ctrl+F1", run: l.ShowShortcuts},
+		{id: tools.ShortcutOpen, title: i18n.T("Add files to the active tool"), keys: toolActionKeys[tools.ShortcutOpen]},
+		{id: tools.ShortcutPrimary, title: i18n.T("Main action of the active tool"), keys: toolActionKeys[tools.ShortcutPrimary]},
+		{id: "popOut", title: i18n.T("Open the active tool in a new window"), keys: "Ctrl+Shift+N", run: func() {
+			if l.active != "" {
+				l.PopOut(l.active)
+			}
+		}},
+		{id: "saveWorkspace", title: i18n.T("Save workspace..."), keys: "Ctrl+Shift+S", run: l.SaveWorkspace},
+		{id: "openWorkspace", title: i18n.T("Open workspace..."), keys: "Ctrl+Shift+O", run: l.OpenWorkspace},
+		{id: "undo", title: i18n.T("Undo"), keys: "Ctrl+Z", run: l.undo},
+		{id: "redo", title: i18n.T("Redo"), keys: "Ctrl+Y", run: l.redo},
+		{id: "nextTool", title: i18n.T("Next tool"), keys: "Ctrl+Tab", run: func() { l.cycleTool(1) }},
+		{id: "previousTool", title: i18n.T("Previous tool"), keys: "Ctrl+Shift+Tab", run: func() { l.cycleTool(-1) }},
+	}
+	for i := range min(len(l.categoryTabs.Items), 9) {
+		bindings = append(bindings, binding{
+			id:    fmt.Sprintf("category%d", i+1),
+			title: i18n.T("Go to %s", l.categoryTabs.Items[i].Text),
+			keys:  fmt.Sprintf("Ctrl+%d", i+1),
+			run:   func() { l.categoryTabs.SelectIndex(i) },
+		})
+	}
+	return bindings
+}
+
+// toolBindings devuelve los atajos que declara la herramienta name en su
+// descriptor. Si tool no es nil, cada uno llama a su RunShortcut; un pánico al
+// ejecutarlo muestra su panel de error.
+func (l *AppLayout) toolBindings(name string, tool tools.Tool) []binding {
+	descriptor, _ := l.registry.Descriptor(name)
+	handler, _ := tool.(tools.ShortcutHandler)
+	bindings := make([]binding, 0, len(descriptor.Shortcuts))
+	for _, s := range descriptor.Shortcuts {
+		b := binding{id: name + "/" + s.ID, title: i18n.T(s.Title), keys: s.Keys}
+		if handler != nil {
+			id := s.ID
+			b.run = l.guard(name, func() { handler.RunShortcut(id) })
+		}
+		if keys, ok := toolActionKeys[s.ID]; ok {
+			b.id, b.keys = s.ID, keys
+		}
+		bindings = append(bindings, b)
+	}
+	return bindings
+}
+
+// keysFor devuelve las teclas de b: las de los ajustes o, si no se han cambiado,
+// las de por defecto.
+func keysFor(store *settings.Store, b binding) string {
+	return store.Section(shortcutsSection).String(b.id, b.keys)
+}
+
+// cycleTool cambia a la herramienta siguiente (delta 1) o anterior (-1) de la
+// categoría visible.
+func (l *AppLayout) cycleTool(delta int) {
+	cv, ok := l.categories[l.categoryTabs.Selected()]
+	if !ok || len(cv.toolTabs.Items) < 2 {
+		return
+	}
+	n := len(cv.toolTabs.Items)
+	cv.toolTabs.SelectIndex((cv.toolTabs.SelectedIndex() + delta + n) % n)
+}
+
+// undo y redo deshacen y rehacen el último cambio de la herramienta visible.
+func (l *AppLayout) undo() { l.undoIn(l.active, l.status) }
+func (l *AppLayout) redo() { l.redoIn(l.active, l.status) }
+
+// undoIn deshace el último cambio de la herramienta name e indica en status qué
+// se ha deshecho.
+func (l *AppLayout) undoIn(name string, status tools.StatusBar) {
+	ctx, ok := l.contexts[name]
+	if !ok {
+		return
+	}
+	// Los comandos son código de la herramienta: un pánico muestra su panel de error.
+	l.guard(name, func() {
+		if c, ok := ctx.Undo.Undo(); ok {
+			status.SetStatus(i18n.T("Undone: %s", c.Title))
+		} else {
+			status.SetStatus(i18n.T("Nothing to undo."))
+		}
+	})()
+}
+
+// redoIn vuelve a aplicar el último cambio deshecho de la herramienta name,
+// protegido como en undoIn.
+func (l *AppLayout) redoIn(name string, status tools.StatusBar) {
+	ctx, ok := l.contexts[name]
+	if !ok {
+		return
+	}
+	l.guard(name, func() {
+		if c, ok := ctx.Undo.Redo(); ok {
+			status.SetStatus(i18n.T("Redone: %s", c.Title))
+		} else {
+			status.SetStatus(i18n.T("Nothing to redo."))
+		}
+	})()
+}
+
+// installAppShortcuts instala (o reinstala, si cambiaron los ajustes) los atajos
+// de la aplicación y los de la herramienta visible.
+func (l *AppLayout) installAppShortcuts() {
+	l.appKeys.clear()
+	for _, b := range l.appBindings() {
+		if b.run != nil {
+			l.appKeys.add(keysFor(l.services.Settings, b), b.run)
+		}
+	}
+	l.installToolShortcuts()
+	for name, p := range l.popouts {
+		l.installPopOutShortcuts(p, name)
+	}
+}
+
+// installToolShortcuts instala en la ventana principal los atajos de la
+// herramienta visible, salvo si está en su propia ventana o ha fallado.
+func (l *AppLayout) installToolShortcuts() {
+	l.toolKeys.clear()
+	name := l.active
+	if _, poppedOut := l.popouts[name]; name == "" || poppedOut || l.failed[name] != nil || !l.registry.IsLoaded(name) {
+		return
+	}
+	tool, _ := l.registry.Loaded(name)
+	for _, b := range l.toolBindings(name, tool) {
+		if b.run == nil {
+			continue
+		}
+		l.toolKeys.add(keysFor(l.services.Settings, b), b.run, l.appKeys)
+	}
+}
+
+// installPopOutShortcuts instala en la ventana separada de la herramienta name la
+// paleta, deshacer y rehacer, y los atajos de la herramienta.
+func (l *AppLayout) installPopOutShortcuts(p *popOutWindow, name string) {
+	p.keys.clear()
+	for _, b := range l.appBindings() {
+		switch b.id {
+		case "palette":
+			p.keys.add(keysFor(l.services.Settings, b), b.run)
+		case "undo":
+			p.keys.add(keysFor(l.services.Settings, b), func() { l.undoIn(name, p.status) })
+		case "redo":
+			p.keys.add(keysFor(l.services.Settings, b), func() { l.redoIn(name, p.status) })
+		}
+	}
+	for _, b := range l.toolBindings(name, p.tool) {
+		if b.run == nil {
+			continue
+		}
+		p.keys.add(keysFor(l.services.Settings, b), b.run, p.keys)
+	}
+}
+
+// registerShortcutSchema registra la sección de atajos de la pantalla de ajustes,
+// con los de la aplicación y los de todas las herramientas.
+func (l *AppLayout) registerShortcutSchema() {
+	var fields []settings.Field
+	field := func(label string, b binding) {
+		fields = append(fields, settings.Field{Key: b.id, Label: label, Kind: settings.Shortcut, Default: b.keys})
+	}
+	for _, b := range l.appBindings() {
+		field(b.title, b)
+	}
+	for _, d := range l.registry.GetAllDescriptors() {
+		for _, b := range l.toolBindings(d.Name, nil) {
+			if _, ok := toolActionKeys[b.id]; !ok {
+				field(i18n.T(d.Name)+": "+b.title, b)
+			}
+		}
+	}
+	l.services.Settings.Register(settings.Schema{
+		Section: shortcutsSection,
+		Title:   "Keyboard shortcuts",
+		Version: 1,
+		Fields:  fields,
+	})
+}
+
+// ShowShortcuts muestra la referencia de atajos de teclado: los de la aplicación
+// y los de cada herramienta, con las teclas que tienen ahora.
+func (l *AppLayout) ShowShortcuts() {
+	store := l.services.Settings
+	group := func(title string, bindings []binding) fyne.CanvasObject {
+		form := widget.NewForm()
+		for _, b := range bindings {
+			keys := keysFor(store, b)
+			if keys == "" {
+				keys = i18n.T("(none)")
+			}
+			form.Append(b.title, widget.NewLabel(keys))
+		}
+		return container.NewVBox(widget.NewLabelWithStyle(title, fyne.TextAlignLeading, fyne.TextStyle{Bold: true}), form)
+	}
+
+	content := container.NewVBox(group(i18n.T("Application"), l.appBindings()))
+	for _, d := range l.registry.GetAllDescriptors() {
+		if bindings := l.toolBindings(d.Name, nil); len(bindings) > 0 {
+			content.Add(widget.NewSeparator())
+			content.Add(group(i18n.T(d.Name), bindings))
+		}
+	}
+
+	var d dialog.Dialog
+	customize := widget.NewButton(i18n.T("Change in Settings..."), func() {
+		d.Hide()
+		l.showSettings()
+	})
+	d = dialog.NewCustom(i18n.T("Keyboard shortcuts"), i18n.T("Close"),
+		container.NewBorder(nil, container.NewHBox(customize), nil, nil, container.NewVScroll(content)), l.window)
+	d.Resize(fyne.NewSize(520, 480))
+	d.Show()
+}
+
+// shortcutSet son atajos instalados en un canvas, para poder quitarlos juntos.
+type shortcutSet struct {
+	canvas    fyne.Canvas
+	installed []fyne.Shortcut
+}
+
+// add instala keys en el canvas. Las teclas vacías (atajo desactivado), las no
+// válidas y las que ya usa alguno de skip se ignoran.
+func (s *shortcutSet) add(keys string, run func(), skip ...*shortcutSet) {
+	if keys == "" {
+		return
+	}
+	parsed, err := parseKeys(keys)
+	if err != nil {
+		slog.Warn("invalid keyboard shortcut", "keys", keys, "err", err)
+		return
+	}
+	shortcut := standardShortcut(parsed)
+	for _, other := range skip {
+		if other.has(shortcut) {
+			return
+		}
+	}
+	s.canvas.AddShortcut(shortcut, func(fyne.Shortcut) { run() })
+	s.installed = append(s.installed, shortcut)
+}
+
+// standardShortcut devuelve el atajo estándar de Fyne con el que el driver
+// entrega shortcut (Ctrl+Z llega como fyne.ShortcutUndo, por ejemplo), o el
+// propio shortcut si no es uno de ellos.
+func standardShortcut(shortcut *desktop.CustomShortcut) fyne.Shortcut {
+	if shortcut.Modifier != fyne.KeyModifierShortcutDefault {
+		return shortcut
+	}
+	switch shortcut.KeyName {
+	case fyne.KeyZ:
+		return &fyne.ShortcutUndo{}
+	case fyne.KeyY:
+		return &fyne.ShortcutRedo{}
+	case fyne.KeyA:
+		return &fyne.ShortcutSelectAll{}
+	case fyne.KeyC:
+		return &fyne.ShortcutCopy{}
+	case fyne.KeyX:
+		return &fyne.ShortcutCut{}
+	case fyne.KeyV:
+		return &fyne.ShortcutPaste{}
+	}
+	return shortcut
+}
+
+func (s *shortcutSet) has(shortcut fyne.Shortcut) bool {
+	for _, installed := range s.installed {
+		if installed.ShortcutName() == shortcut.ShortcutName() {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *shortcutSet) clear() {
+	for _, shortcut := range s.installed {
+		s.canvas.RemoveShortcut(shortcut)
+	}
+	s.installed = nil
+}
+
+// Modificadores y teclas con nombre que aceptan los atajos. "Ctrl" es Cmd en macOS.
+var (
+	keyModifiers = []struct {
+		name     string
+		modifier fyne.KeyModifier
+	}{
+		{"Ctrl", fyne.KeyModifierShortcutDefault},
+		{"Alt", fyne.KeyModifierAlt},
+		{"Shift", fyne.KeyModifierShift},
+		{"Super", fyne.KeyModifierSuper},
+	}
+	namedKeys = map[string]fyne.KeyName{
+		"enter": fyne.KeyReturn, "return": fyne.KeyReturn, "tab": fyne.KeyTab, "space": fyne.KeySpace,
+		"esc": fyne.KeyEscape, "escape": fyne.KeyEscape, "backspace": fyne.KeyBackspace,
+		"delete": fyne.KeyDelete, "del": fyne.KeyDelete, "insert": fyne.KeyInsert,
+		"home": fyne.KeyHome, "end": fyne.KeyEnd, "pageup": fyne.KeyPageUp, "pagedown": fyne.KeyPageDown,
+		"up": fyne.KeyUp, "down": fyne.KeyDown, "left": fyne.KeyLeft, "right": fyne.KeyRight,
+	}
+)
+
+// parseKeys convierte una combinación como "Ctrl+Shift+O" en un atajo de Fyne.
+// Hace falta al menos Ctrl, Alt o Super: sin ellos la tecla se escribiría en el
+// campo de texto que tenga el foco.
+func parseKeys(keys string) (*desktop.CustomShortcut, error) {
+	parts := strings.Split(keys, "+")
+	shortcut := &desktop.CustomShortcut{}
+	for _, part := range parts[:len(parts)-1] {
+		found := false
+		for _, m := range keyModifiers {
+			if strings.EqualFold(strings.TrimSpace(part), m.name) {
+				shortcut.Modifier |= m.modifier
+				found = true
+			}
+		}
+		if !found {
+			return nil, errors.New(i18n.T("unknown modifier %q", strings.TrimSpace(part)))
+		}
+	}
+	if shortcut.Modifier&^fyne.KeyModifierShift == 0 {
+		return nil, errors.New(i18n.T("a shortcut needs Ctrl, Alt or Super"))
+	}
+
+	key := strings.ToUpper(strings.TrimSpace(parts[len(parts)-1]))
+	switch {
+	case len(key) == 1 && (key[0] >= '0' && key[0] <= '9' || key[0] >= 'A' && key[0] <= 'Z'):
+		shortcut.KeyName = fyne.KeyName(key)
+	case len(key) >= 2 && key[0] == 'F' && isFunctionKey(key[1:]):
+		shortcut.KeyName = fyne.KeyName(key)
+	default:
+		name, ok := namedKeys[strings.ToLower(key)]
+		if !ok {
+			return nil, errors.New(i18n.T("unknown key %q", strings.TrimSpace(parts[len(parts)-1])))
+		}
+		shortcut.KeyName = name
+	}
+	return shortcut, nil
+}
+
+// isFunctionKey indica si n es el número de una tecla de función (1 a 12).
+func isFunctionKey(n string) bool {
+	var i int
+	_, err := fmt.Sscanf(n, "%d", &i)
+	return err == nil && fmt.Sprint(i) == n && i >= 1 && i <= 12
+}
+
+// formatKeys es la forma normalizada de una combinación válida, como la guarda
+// la pantalla de ajustes.
+func formatKeys(shortcut *desktop.CustomShortcut) string {
+	var parts []string
+	rest := shortcut.Modifier
+	for _, m := range keyModifiers {
+		if rest&m.modifier != 0 {
+			parts = append(parts, m.name)
+			rest &^= m.modifier
+		}
+	}
+	key := string(shortcut.KeyName)
+	switch shortcut.KeyName {
+	case fyne.KeyReturn:
+		key = "Enter"
+	case fyne.KeyPageUp:
+		key = "PageUp"
+	case fyne.KeyPageDown:
+		key = "PageDown"
+	case fyne.KeyBackspace:
+		key = "Backspace"
+	}
+	return strings.Join(append(parts, key), "+")
+}
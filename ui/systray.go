@@ -3,64 +3,116 @@ package ui
 import (
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/driver/desktop"
-	"fyne.io/fyne/v2/theme"
-	"github.com/Lec7ral/MultiTool/tools/network/networkswitcher"
-	"github.com/Lec7ral/MultiTool/tools/profiles"
+	"github.com/Lec7ral/MultiTool/assets"
+	"github.com/Lec7ral/MultiTool/i18n"
+	"github.com/Lec7ral/MultiTool/tools"
 )
 
-// InstallSystray configura e instala la bandeja del sistema y su menú.
-func InstallSystray(app fyne.App, showWindow func()) {
-	if desk, ok := app.(desktop.App); ok {
-		// Función para construir/reconstruir el menú
-		buildMenu := func() {
-			iconResource, err := fyne.LoadResourceFromPath("icon.png")
-			if err != nil {
-				fyne.LogError("Failed to load systray icon", err)
-				desk.SetSystemTrayIcon(theme.FyneLogo())
-			} else {
-				desk.SetSystemTrayIcon(iconResource)
-			}
+// noSystray es el menú de la bandeja cuando no hay bandeja (o todavía no se ha instalado).
+type noSystray struct{}
 
-			menu := fyne.NewMenu("Toolbox",
-				fyne.NewMenuItem("Open", showWindow),
-			)
+func (noSystray) Refresh() {}
 
-			// Cargar los perfiles de red desde el inicio.
-			loadedProfiles, err := profiles.LoadProfiles()
-			if err == nil && len(loadedProfiles) > 0 {
-				profileSubMenu := fyne.NewMenu("")
-				for _, p := range loadedProfiles {
-					profile := p
-					item := fyne.NewMenuItem(profile.Name, func() {
-						go func() {
-							if err := networkswitcher.ApplyProfile(profile); err != nil {
-								app.SendNotification(&fyne.Notification{Title: "Toolbox", Content: "Failed to apply profile " + profile.Name})
-							} else {
-								app.SendNotification(&fyne.Notification{Title: "Toolbox", Content: "Profile '" + profile.Name + "' applied."})
-							}
-						}()
-					})
-					profileSubMenu.Items = append(profileSubMenu.Items, item)
-				}
+// systrayMenu construye el menú de la bandeja: las entradas fijas (abrir, salir)
+// y las que las herramientas declaran en tools.ToolDescriptor.Systray. No crea
+// instancias de las herramientas, solo un contexto sin ventana para cada una.
+type systrayMenu struct {
+	app        fyne.App
+	desk       desktop.App
+	showWindow func()
+	beforeQuit func()
+	services   *AppServices
+	contexts   map[string]*tools.ToolContext
+}
 
-				modeMenuItem := fyne.NewMenuItem("Mode", nil)
-				modeMenuItem.ChildMenu = profileSubMenu
+// InstallSystray configura e instala la bandeja del sistema y su menú.
+// beforeQuit se llama al elegir "Salir", antes de cerrar la aplicación, cuando
+// las ventanas nativas todavía existen.
+func InstallSystray(app fyne.App, showWindow, beforeQuit func(), services *AppServices) {
+	desk, ok := app.(desktop.App)
+	if !ok {
+		return
+	}
 
-				menu.Items = append(menu.Items, fyne.NewMenuItemSeparator())
-				menu.Items = append(menu.Items, modeMenuItem)
-			}
+	desk.SetSystemTrayIcon(assets.AppIcon)
 
-			menu.Items = append(menu.Items, fyne.NewMenuItemSeparator())
-			menu.Items = append(menu.Items, fyne.NewMenuItem("Quit", func() {
-				app.Quit()
-			}))
+	m := &systrayMenu{
+		app:        app,
+		desk:       desk,
+		showWindow: showWindow,
+		beforeQuit: beforeQuit,
+		services:   services,
+		contexts:   make(map[string]*tools.ToolContext),
+	}
+	services.Systray = m
+	m.build() // Construir el menú inicial
+}
 
-			desk.SetSystemTrayMenu(menu)
+// Refresh reconstruye el menú. Puede llamarse desde cualquier goroutine.
+func (m *systrayMenu) Refresh() {
+	fyne.Do(m.build)
+}
+
+// build construye el menú y lo instala en la bandeja.
+func (m *systrayMenu) build() {
+	menu := fyne.NewMenu("Toolbox",
+		fyne.NewMenuItem(i18n.T("Open"), m.showWindow),
+	)
+
+	for _, d := range tools.Descriptors() {
+		if d.Systray == nil {
+			continue
+		}
+		ctx := m.contextFor(d.Name)
+		var items []*fyne.MenuItem
+		if err := tools.Safely(d.Name, func() { items = d.Systray(ctx) }); err != nil {
+			continue // Safely ya lo registra; la próxima vez se vuelve a intentar.
+		}
+		protectItems(ctx, items)
+		if len(items) == 0 {
+			continue
 		}
+		menu.Items = append(menu.Items, fyne.NewMenuItemSeparator())
+		menu.Items = append(menu.Items, items...)
+	}
+
+	// Los flujos de trabajo guardados.
+	workflows := fyne.NewMenuItem(i18n.T("Workflows"), nil)
+	workflows.ChildMenu = fyne.NewMenu("", workflowItems(m.services)...)
+	menu.Items = append(menu.Items, fyne.NewMenuItemSeparator(), workflows)
+
+	menu.Items = append(menu.Items, fyne.NewMenuItemSeparator())
+	menu.Items = append(menu.Items, fyne.NewMenuItem(i18n.T("Quit"), func() {
+		m.services.Jobs.CancelAll()
+		m.beforeQuit()
+		m.app.Quit()
+	}))
+
+	m.desk.SetSystemTrayMenu(menu)
+}
 
-		buildMenu() // Construir el menú inicial
+// protectItems protege con ctx.Safe la acción de cada entrada de items y de sus
+// submenús, para que un pánico en ella no cierre la aplicación con la bandeja.
+func protectItems(ctx *tools.ToolContext, items []*fyne.MenuItem) {
+	for _, item := range items {
+		if item == nil {
+			continue
+		}
+		if item.Action != nil {
+			item.Action = ctx.Safe(item.Action)
+		}
+		if item.ChildMenu != nil {
+			protectItems(ctx, item.ChildMenu.Items)
+		}
+	}
+}
 
-		// Pasar la función de reconstrucción a la herramienta de red
-		networkswitcher.SetSystrayCallback(buildMenu)
+// contextFor devuelve (creándolo la primera vez) el contexto, sin ventana, de una herramienta.
+func (m *systrayMenu) contextFor(name string) *tools.ToolContext {
+	if ctx, ok := m.contexts[name]; ok {
+		return ctx
 	}
+	ctx := BackgroundContext(m.services, name)
+	m.contexts[name] = ctx
+	return ctx
 }
@@ -2,50 +2,204 @@ package ui
 
 import (
 	"image/color"
+	"log/slog"
+	"sort"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/theme"
+	"github.com/Lec7ral/MultiTool/settings"
 )
 
-// CustomTheme defines the custom color scheme for the application
+// Names of the built-in themes, as shown in the settings.
+const (
+	ThemeSystem       = "System"
+	ThemeDark         = "Dark"
+	ThemeLight        = "Light"
+	ThemeHighContrast = "High contrast"
+)
+
+// Font slots that a theme can override.
+const (
+	fontRegular    = "regular"
+	fontBold       = "bold"
+	fontItalic     = "italic"
+	fontBoldItalic = "boldItalic"
+	fontMonospace  = "monospace"
+	fontSymbol     = "symbol"
+)
+
+// CustomTheme is a theme built on top of baseTheme that overrides some of its
+// colors, sizes, fonts and icons. If variant is nil the theme follows the variant
+// (light or dark) chosen by the operating system.
 type CustomTheme struct {
 	baseTheme fyne.Theme
+	variant   *fyne.ThemeVariant
+	colors    map[fyne.ThemeColorName]color.Color
+	sizes     map[fyne.ThemeSizeName]float32
+	fonts     map[string]fyne.Resource
+	icons     map[fyne.ThemeIconName]fyne.Resource
 }
 
 // Color returns the color for a specific name and variant
 func (c *CustomTheme) Color(name fyne.ThemeColorName, variant fyne.ThemeVariant) color.Color {
-	switch name {
-	case theme.ColorNamePrimary:
-		return color.NRGBA{R: 45, G: 110, B: 175, A: 255} // #2D6EAF
-	case theme.ColorNameBackground:
-		return color.NRGBA{R: 33, G: 33, B: 33, A: 255} // #212121
-	case theme.ColorNameForeground:
-		return color.NRGBA{R: 248, G: 249, B: 250, A: 255} // #F8F9FA
-	case theme.ColorNameMenuBackground:
-		return color.NRGBA{R: 44, G: 44, B: 44, A: 255} // #2C2C2C
-	case theme.ColorNameInputBackground:
-		return color.NRGBA{R: 44, G: 44, B: 44, A: 255} // #2C2C2C
-	default:
-		return theme.DarkTheme().Color(name, variant)
+	if col, ok := c.colors[name]; ok {
+		return col
+	}
+	if c.variant != nil {
+		variant = *c.variant
 	}
+	return c.baseTheme.Color(name, variant)
 }
 
 // Font returns the font for a specific style
 func (c *CustomTheme) Font(style fyne.TextStyle) fyne.Resource {
-	return theme.DarkTheme().Font(style)
+	if font, ok := c.fonts[fontSlot(style)]; ok {
+		return font
+	}
+	return c.baseTheme.Font(style)
 }
 
-// Icon returns the icon for a specific name
+// Icon returns the icon for a specific name. Besides Fyne's icons, themes can
+// replace MultiTool's own (see the assets package).
 func (c *CustomTheme) Icon(name fyne.ThemeIconName) fyne.Resource {
-	return theme.DarkTheme().Icon(name)
+	if icon, ok := c.icons[name]; ok {
+		return icon
+	}
+	return c.baseTheme.Icon(name)
 }
 
 // Size returns the size for a specific name
 func (c *CustomTheme) Size(name fyne.ThemeSizeName) float32 {
-	return theme.DarkTheme().Size(name)
+	if size, ok := c.sizes[name]; ok {
+		return size
+	}
+	return c.baseTheme.Size(name)
+}
+
+// fontSlot returns the font slot used for a text style.
+func fontSlot(style fyne.TextStyle) string {
+	switch {
+	case style.Monospace:
+		return fontMonospace
+	case style.Symbol:
+		return fontSymbol
+	case style.Bold && style.Italic:
+		return fontBoldItalic
+	case style.Bold:
+		return fontBold
+	case style.Italic:
+		return fontItalic
+	default:
+		return fontRegular
+	}
 }
 
-// ApplyTheme sets the custom theme for the application
-func ApplyTheme(app fyne.App) {
-	app.Settings().SetTheme(&CustomTheme{baseTheme: theme.DarkTheme()})
+// builtinTheme returns one of the built-in themes, or nil if name is unknown.
+func builtinTheme(name string) *CustomTheme {
+	dark, light := theme.VariantDark, theme.VariantLight
+
+	switch name {
+	case ThemeSystem:
+		return &CustomTheme{baseTheme: theme.DefaultTheme()}
+	case ThemeDark:
+		return &CustomTheme{
+			baseTheme: theme.DefaultTheme(),
+			variant:   &dark,
+			colors: map[fyne.ThemeColorName]color.Color{
+				theme.ColorNamePrimary:         color.NRGBA{R: 45, G: 110, B: 175, A: 255},  // #2D6EAF
+				theme.ColorNameBackground:      color.NRGBA{R: 33, G: 33, B: 33, A: 255},    // #212121
+				theme.ColorNameForeground:      color.NRGBA{R: 248, G: 249, B: 250, A: 255}, // #F8F9FA
+				theme.ColorNameMenuBackground:  color.NRGBA{R: 44, G: 44, B: 44, A: 255},    // #2C2C2C
+				theme.ColorNameInputBackground: color.NRGBA{R: 44, G: 44, B: 44, A: 255},    // #2C2C2C
+			},
+		}
+	case ThemeLight:
+		return &CustomTheme{
+			baseTheme: theme.DefaultTheme(),
+			variant:   &light,
+			colors: map[fyne.ThemeColorName]color.Color{
+				theme.ColorNamePrimary: color.NRGBA{R: 45, G: 110, B: 175, A: 255}, // #2D6EAF
+			},
+		}
+	case ThemeHighContrast:
+		black := color.NRGBA{A: 255}
+		white := color.NRGBA{R: 255, G: 255, B: 255, A: 255}
+		yellow := color.NRGBA{R: 255, G: 214, B: 0, A: 255} // #FFD600
+		return &CustomTheme{
+			baseTheme: theme.DefaultTheme(),
+			variant:   &dark,
+			colors: map[fyne.ThemeColorName]color.Color{
+				theme.ColorNameBackground:        black,
+				theme.ColorNameForeground:        white,
+				theme.ColorNamePrimary:           yellow,
+				theme.ColorNameFocus:             yellow,
+				theme.ColorNameHyperlink:         color.NRGBA{R: 0, G: 255, B: 255, A: 255}, // #00FFFF
+				theme.ColorNameButton:            black,
+				theme.ColorNameInputBackground:   black,
+				theme.ColorNameInputBorder:       white,
+				theme.ColorNameMenuBackground:    black,
+				theme.ColorNameOverlayBackground: black,
+				theme.ColorNameSeparator:         white,
+				theme.ColorNamePlaceHolder:       color.NRGBA{R: 200, G: 200, B: 200, A: 255},
+				theme.ColorNameDisabled:          color.NRGBA{R: 150, G: 150, B: 150, A: 255},
+				theme.ColorNameSelection:         color.NRGBA{R: 255, G: 214, B: 0, A: 110},
+			},
+			sizes: map[fyne.ThemeSizeName]float32{
+				theme.SizeNameText:               15,
+				theme.SizeNameInputBorder:        2,
+				theme.SizeNameSeparatorThickness: 2,
+			},
+		}
+	}
+	return nil
+}
+
+// ThemeNames returns the names of the built-in themes followed by the user
+// themes found in the themes folder, sorted by name.
+func ThemeNames() []string {
+	names := []string{ThemeSystem, ThemeDark, ThemeLight, ThemeHighContrast}
+	var user []string
+	for name := range findUserThemes() {
+		if builtinTheme(name) == nil {
+			user = append(user, name)
+		}
+	}
+	sort.Strings(user)
+	return append(names, user...)
+}
+
+// loadTheme returns the theme called name. Unknown or broken themes fall back
+// to the default dark theme.
+func loadTheme(name string) fyne.Theme {
+	if t := builtinTheme(name); t != nil {
+		return t
+	}
+	if path, ok := findUserThemes()[name]; ok {
+		t, err := loadThemeFile(path)
+		if err == nil {
+			return t
+		}
+		slog.Error("failed to load theme", "path", path, "err", err)
+	}
+	return builtinTheme(ThemeDark)
+}
+
+// ApplyTheme sets the theme chosen in the settings and re-applies it whenever
+// the setting changes or the theme files are edited.
+func ApplyTheme(app fyne.App, store *settings.Store) {
+	section := store.Section(settings.AppSection)
+	apply := func() {
+		app.Settings().SetTheme(loadTheme(section.String(SettingTheme, ThemeDark)))
+	}
+	apply()
+	store.OnChange(func(sectionName, key string) {
+		if sectionName == settings.AppSection && key == SettingTheme {
+			fyne.Do(apply)
+		}
+	})
+	watchUserThemes(func() {
+		// New or removed files change the list offered in the settings.
+		store.Register(appSettingsSchema())
+		fyne.Do(apply)
+	})
 }
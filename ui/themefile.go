@@ -0,0 +1,234 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"github.com/BurntSushi/toml"
+	"github.com/Lec7ral/MultiTool/config"
+	"github.com/fsnotify/fsnotify"
+)
+
+// themeFile is the content of a user theme file (JSON or TOML) in the themes
+// folder of the configuration directory. Colors, sizes and icons use Fyne's
+// names ("primary", "background", "text", "padding", "search"...), plus
+// MultiTool's icon names ("multitool.pdf"...); font and icon paths are relative
+// to the theme file.
+//
+//	name = "Solarized"
+//	base = "Dark"
+//
+//	[colors]
+//	background = "#002B36"
+//	primary = "#268BD2"
+//
+//	[sizes]
+//	text = 15
+//
+//	[fonts]
+//	regular = "fonts/Inter-Regular.ttf"
+//
+//	[icons]
+//	"multitool.pdf" = "icons/pdf.svg"
+type themeFile struct {
+	Name   string             `json:"name" toml:"name"`
+	Base   string             `json:"base" toml:"base"` // Built-in theme to extend; System by default.
+	Colors map[string]string  `json:"colors" toml:"colors"`
+	Sizes  map[string]float32 `json:"sizes" toml:"sizes"`
+	Fonts  map[string]string  `json:"fonts" toml:"fonts"`
+	Icons  map[string]string  `json:"icons" toml:"icons"`
+}
+
+// themeReloadDelay groups the many events an editor produces when saving a file.
+const themeReloadDelay = 300 * time.Millisecond
+
+// themesDir returns the folder with the user themes.
+func themesDir() string {
+	return config.Path("themes")
+}
+
+// isThemeFile reports whether path has a supported theme file extension.
+func isThemeFile(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json", ".toml":
+		return true
+	}
+	return false
+}
+
+// readThemeFile decodes a theme file, using the file name as the theme name
+// when the file does not set one.
+func readThemeFile(path string) (themeFile, error) {
+	var tf themeFile
+	if strings.EqualFold(filepath.Ext(path), ".toml") {
+		if _, err := toml.DecodeFile(path, &tf); err != nil {
+			return tf, err
+		}
+	} else {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return tf, err
+		}
+		if err := json.Unmarshal(raw, &tf); err != nil {
+			return tf, err
+		}
+	}
+	if tf.Name == "" {
+		tf.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	return tf, nil
+}
+
+// findUserThemes returns the path of every user theme, keyed by theme name.
+func findUserThemes() map[string]string {
+	found := make(map[string]string)
+	entries, err := os.ReadDir(themesDir())
+	if err != nil {
+		return found
+	}
+	for _, entry := range entries {
+		path := filepath.Join(themesDir(), entry.Name())
+		if entry.IsDir() || !isThemeFile(path) {
+			continue
+		}
+		tf, err := readThemeFile(path)
+		if err != nil {
+			slog.Warn("ignoring invalid theme file", "path", path, "err", err)
+			continue
+		}
+		found[tf.Name] = path
+	}
+	return found
+}
+
+// loadThemeFile builds the theme described by a theme file.
+func loadThemeFile(path string) (*CustomTheme, error) {
+	tf, err := readThemeFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	baseName := tf.Base
+	if baseName == "" {
+		baseName = ThemeSystem
+	}
+	base := builtinTheme(baseName)
+	if base == nil {
+		return nil, fmt.Errorf("unknown base theme %q", tf.Base)
+	}
+
+	t := &CustomTheme{
+		baseTheme: base,
+		variant:   base.variant,
+		colors:    make(map[fyne.ThemeColorName]color.Color),
+		sizes:     make(map[fyne.ThemeSizeName]float32),
+		fonts:     make(map[string]fyne.Resource),
+		icons:     make(map[fyne.ThemeIconName]fyne.Resource),
+	}
+	for name, value := range tf.Colors {
+		col, err := parseHexColor(value)
+		if err != nil {
+			return nil, fmt.Errorf("color %q: %w", name, err)
+		}
+		t.colors[fyne.ThemeColorName(name)] = col
+	}
+	for name, size := range tf.Sizes {
+		t.sizes[fyne.ThemeSizeName(name)] = size
+	}
+	for slot, fontPath := range tf.Fonts {
+		switch slot {
+		case fontRegular, fontBold, fontItalic, fontBoldItalic, fontMonospace, fontSymbol:
+		default:
+			return nil, fmt.Errorf("unknown font %q", slot)
+		}
+		if !filepath.IsAbs(fontPath) {
+			fontPath = filepath.Join(filepath.Dir(path), fontPath)
+		}
+		font, err := fyne.LoadResourceFromPath(fontPath)
+		if err != nil {
+			return nil, fmt.Errorf("font %q: %w", slot, err)
+		}
+		t.fonts[slot] = font
+	}
+	for name, iconPath := range tf.Icons {
+		if !filepath.IsAbs(iconPath) {
+			iconPath = filepath.Join(filepath.Dir(path), iconPath)
+		}
+		icon, err := fyne.LoadResourceFromPath(iconPath)
+		if err != nil {
+			return nil, fmt.Errorf("icon %q: %w", name, err)
+		}
+		t.icons[fyne.ThemeIconName(name)] = icon
+	}
+	return t, nil
+}
+
+// parseHexColor parses "#RRGGBB" or "#RRGGBBAA".
+func parseHexColor(s string) (color.Color, error) {
+	var r, g, b uint8
+	a := uint8(255)
+	s = strings.TrimPrefix(s, "#")
+	var err error
+	switch len(s) {
+	case 6:
+		_, err = fmt.Sscanf(s, "%02x%02x%02x", &r, &g, &b)
+	case 8:
+		_, err = fmt.Sscanf(s, "%02x%02x%02x%02x", &r, &g, &b, &a)
+	default:
+		return nil, fmt.Errorf("invalid color %q, expected #RRGGBB or #RRGGBBAA", s)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid color %q: %w", s, err)
+	}
+	return color.NRGBA{R: r, G: g, B: b, A: a}, nil
+}
+
+// watchUserThemes calls onChange (from a background goroutine) whenever a file
+// in the themes folder is created, edited or removed.
+func watchUserThemes(onChange func()) {
+	dir := themesDir()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		slog.Warn("cannot create themes folder", "path", dir, "err", err)
+		return
+	}
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		slog.Warn("cannot watch themes folder", "err", err)
+		return
+	}
+	if err := watcher.Add(dir); err != nil {
+		slog.Warn("cannot watch themes folder", "path", dir, "err", err)
+		watcher.Close()
+		return
+	}
+
+	go func() {
+		var reload <-chan time.Time
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if isThemeFile(event.Name) {
+					reload = time.After(themeReloadDelay)
+				}
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				slog.Warn("themes folder watcher", "err", err)
+			case <-reload:
+				reload = nil
+				onChange()
+			}
+		}
+	}()
+}
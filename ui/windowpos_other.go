@@ -0,0 +1,18 @@
+//go:build !windows
+
+package ui
+
+import "fyne.io/fyne/v2"
+
+// Fyne no expone la posición de las ventanas y fuera de Windows no la gestionamos
+// a mano, así que solo se recuerda el tamaño.
+
+func windowPosition(fyne.Window) (x, y int, ok bool) {
+	return 0, 0, false
+}
+
+func setWindowPosition(fyne.Window, int, int) {}
+
+func positionVisible(int, int) bool {
+	return true
+}
@@ -0,0 +1,92 @@
+package ui
+
+import (
+	"syscall"
+	"unsafe"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver"
+)
+
+var (
+	user32            = syscall.NewLazyDLL("user32.dll")
+	procGetWindowRect = user32.NewProc("GetWindowRect")
+	procSetWindowPos  = user32.NewProc("SetWindowPos")
+
+	procMonitorFromRect = user32.NewProc("MonitorFromRect")
+	procGetMonitorInfo  = user32.NewProc("GetMonitorInfoW")
+)
+
+const (
+	swpNoSize     = 0x0001
+	swpNoZOrder   = 0x0004
+	swpNoActivate = 0x0010
+
+	monitorDefaultToNull = 0x0000
+)
+
+type rect struct {
+	Left, Top, Right, Bottom int32
+}
+
+// monitorInfo es la estructura MONITORINFO de GetMonitorInfoW.
+type monitorInfo struct {
+	Size    uint32
+	Monitor rect
+	Work    rect
+	Flags   uint32
+}
+
+// titleBarInset es cuánto se mete el punto que se comprueba en positionVisible
+// dentro de la esquina de la ventana, para caer en la barra de título.
+const titleBarInset = 16
+
+// windowPosition devuelve la posición en pantalla de la ventana nativa de w.
+func windowPosition(w fyne.Window) (x, y int, ok bool) {
+	native, isNative := w.(driver.NativeWindow)
+	if !isNative {
+		return 0, 0, false
+	}
+	native.RunNative(func(ctx any) {
+		wctx, isWin := ctx.(driver.WindowsWindowContext)
+		if !isWin || wctx.HWND == 0 {
+			return
+		}
+		var r rect
+		if ret, _, _ := procGetWindowRect.Call(wctx.HWND, uintptr(unsafe.Pointer(&r))); ret != 0 {
+			x, y, ok = int(r.Left), int(r.Top), true
+		}
+	})
+	return x, y, ok
+}
+
+// setWindowPosition mueve la ventana nativa de w sin cambiar su tamaño.
+func setWindowPosition(w fyne.Window, x, y int) {
+	native, isNative := w.(driver.NativeWindow)
+	if !isNative {
+		return
+	}
+	native.RunNative(func(ctx any) {
+		if wctx, isWin := ctx.(driver.WindowsWindowContext); isWin && wctx.HWND != 0 {
+			procSetWindowPos.Call(wctx.HWND, 0, uintptr(x), uintptr(y), 0, 0, swpNoSize|swpNoZOrder|swpNoActivate)
+		}
+	})
+}
+
+// positionVisible indica si una ventana en (x, y) tendría la barra de título
+// dentro del área de trabajo de algún monitor conectado, de modo que el usuario
+// pueda alcanzarla y arrastrarla.
+func positionVisible(x, y int) bool {
+	px, py := int32(x+titleBarInset), int32(y+titleBarInset)
+	r := rect{Left: px, Top: py, Right: px + 1, Bottom: py + 1}
+	monitor, _, _ := procMonitorFromRect.Call(uintptr(unsafe.Pointer(&r)), monitorDefaultToNull)
+	if monitor == 0 {
+		return false
+	}
+	info := monitorInfo{Size: uint32(unsafe.Sizeof(monitorInfo{}))}
+	if ret, _, _ := procGetMonitorInfo.Call(monitor, uintptr(unsafe.Pointer(&info))); ret == 0 {
+		return false
+	}
+	work := info.Work
+	return px >= work.Left && px < work.Right && py >= work.Top && py < work.Bottom
+}
@@ -0,0 +1,414 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/Lec7ral/MultiTool/i18n"
+	"github.com/Lec7ral/MultiTool/jobs"
+	"github.com/Lec7ral/MultiTool/tools"
+	"github.com/Lec7ral/MultiTool/workflow"
+)
+
+// workflowWindow es la ventana del editor de flujos de trabajo, si está abierta.
+var workflowWindow fyne.Window
+
+// workflowItems devuelve una entrada de menú por flujo guardado, para la bandeja
+// del sistema. Los que no necesitan datos se ejecutan directamente; los demás
+// abren el editor para elegir los datos.
+func workflowItems(services *AppServices) []*fyne.MenuItem {
+	workflows, err := workflow.Load()
+	if err != nil {
+		slog.Error("failed to load workflows", "err", err)
+	}
+	items := make([]*fyne.MenuItem, 0, len(workflows)+2)
+	for _, w := range workflows {
+		wf := w
+		label := wf.Name
+		if wf.Input() != tools.NoData {
+			label += "..."
+		}
+		items = append(items, fyne.NewMenuItem(label, func() {
+			if wf.Input() == tools.NoData {
+				runWorkflow(nil, services, wf)
+			} else {
+				showWorkflows(services, wf.Name)
+			}
+		}))
+	}
+	if len(items) > 0 {
+		items = append(items, fyne.NewMenuItemSeparator())
+	}
+	items = append(items, fyne.NewMenuItem(i18n.T("Edit workflows..."), func() { showWorkflows(services, "") }))
+	return items
+}
+
+// runWorkflow pide los datos que necesita wf (archivos o texto) y lo ejecuta como
+// tarea en segundo plano. parent es la ventana de los diálogos; si es nil (desde
+// la bandeja), wf no debe necesitar datos y los errores se notifican.
+func runWorkflow(parent fyne.Window, services *AppServices, wf workflow.Workflow) {
+	if err := wf.Validate(); err != nil {
+		showWorkflowError(parent, wf, err)
+		return
+	}
+	switch wf.Input() {
+	case tools.Files:
+		askWorkflowFiles(parent, func(files []string) {
+			submitWorkflow(services, wf, tools.FilesData(files...))
+		})
+	case tools.Text:
+		entry := widget.NewMultiLineEntry()
+		entry.SetMinRowsVisible(6)
+		d := dialog.NewForm(i18n.T("Run workflow %s", wf.Name), i18n.T("Run"), i18n.T("Cancel"),
+			[]*widget.FormItem{widget.NewFormItem(i18n.T("Input text"), entry)},
+			func(ok bool) {
+				if ok {
+					submitWorkflow(services, wf, tools.TextData(entry.Text))
+				}
+			}, parent)
+		d.Resize(fyne.NewSize(500, 300))
+		d.Show()
+	default:
+		submitWorkflow(services, wf, tools.Data{})
+	}
+}
+
+// askWorkflowFiles pide la lista de archivos de entrada de un flujo.
+func askWorkflowFiles(parent fyne.Window, run func(files []string)) {
+	entry := widget.NewMultiLineEntry()
+	entry.SetPlaceHolder(i18n.T("One file per line"))
+	entry.SetMinRowsVisible(6)
+	add := widget.NewButtonWithIcon(i18n.T("Add file..."), theme.FileIcon(), func() {
+		dialog.ShowFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err != nil || reader == nil {
+				return
+			}
+			reader.Close()
+			text := strings.TrimRight(entry.Text, "\n")
+			if text != "" {
+				text += "\n"
+			}
+			entry.SetText(text + localPath(reader.URI()))
+		}, parent)
+	})
+
+	d := dialog.NewCustomConfirm(i18n.T("Input files"), i18n.T("Run"), i18n.T("Cancel"),
+		container.NewBorder(nil, container.NewHBox(add), nil, nil, entry),
+		func(ok bool) {
+			if !ok {
+				return
+			}
+			var files []string
+			for _, line := range strings.Split(entry.Text, "\n") {
+				if line = strings.TrimSpace(line); line != "" {
+					files = append(files, line)
+				}
+			}
+			if len(files) == 0 {
+				dialog.ShowInformation(i18n.T("Input files"), i18n.T("Add at least one file."), parent)
+				return
+			}
+			run(files)
+		}, parent)
+	d.Resize(fyne.NewSize(560, 320))
+	d.Show()
+}
+
+// submitWorkflow ejecuta wf con in como tarea y avisa al terminar.
+func submitWorkflow(services *AppServices, wf workflow.Workflow, in tools.Data) {
+	notifier := appNotifier{app: fyne.CurrentApp()}
+	services.Jobs.Submit(workflow.JobTool, i18n.T("Workflow %s", wf.Name), func(ctx context.Context, r jobs.Reporter) error {
+		out, err := wf.Run(ctx, in, r)
+		if err != nil {
+			if !errors.Is(err, context.Canceled) {
+				slog.Error("workflow failed", "workflow", wf.Name, "err", err)
+				notifier.Notify(i18n.T("Workflow %s failed", wf.Name), err.Error())
+			}
+			return err
+		}
+		result := strings.Join(out.Files, "\n")
+		if out.Kind == tools.Text {
+			result = out.Text
+		}
+		if result != "" {
+			r.Logf("%s", result)
+		}
+		slog.Info("workflow finished", "workflow", wf.Name)
+		notifier.Notify(i18n.T("Workflow %s finished", wf.Name), result)
+		return nil
+	})
+}
+
+// showWorkflowError muestra un error de un flujo en parent o, sin ventana, como notificación.
+func showWorkflowError(parent fyne.Window, wf workflow.Workflow, err error) {
+	if parent != nil {
+		dialog.ShowError(err, parent)
+		return
+	}
+	appNotifier{app: fyne.CurrentApp()}.Notify(i18n.T("Workflow %s failed", wf.Name), err.Error())
+}
+
+// operationLabel es el texto con el que el editor muestra una operación.
+func operationLabel(ref workflow.Ref) string {
+	return fmt.Sprintf("%s: %s (%s → %s)", i18n.T(ref.Tool), i18n.T(ref.Operation.Title),
+		dataKindLabel(ref.Operation.Input), dataKindLabel(ref.Operation.Output))
+}
+
+func dataKindLabel(kind tools.DataKind) string {
+	switch kind {
+	case tools.Files:
+		return i18n.T("files")
+	case tools.Text:
+		return i18n.T("text")
+	}
+	return i18n.T("nothing")
+}
+
+// showWorkflows abre el editor de flujos de trabajo, o lo trae al frente si ya
+// está abierto, con el flujo name seleccionado (si existe).
+func showWorkflows(services *AppServices, name string) {
+	if workflowWindow != nil {
+		workflowWindow.Show()
+		workflowWindow.RequestFocus()
+		return
+	}
+
+	w := fyne.CurrentApp().NewWindow(i18n.T("Workflows"))
+	workflowWindow = w
+	w.SetOnClosed(func() { workflowWindow = nil })
+	w.Resize(fyne.NewSize(900, 560))
+
+	workflows, err := workflow.Load()
+	if err != nil {
+		slog.Error("failed to load workflows", "err", err)
+		dialog.ShowError(err, w)
+	}
+
+	refs := workflow.Operations()
+	refLabels := make([]string, len(refs))
+	for i, ref := range refs {
+		refLabels[i] = operationLabel(ref)
+	}
+
+	// draft es el flujo que se está editando; selected, su posición en workflows
+	// (-1 si todavía no se ha guardado).
+	var draft workflow.Workflow
+	selected := -1
+
+	nameEntry := widget.NewEntry()
+	nameEntry.SetPlaceHolder(i18n.T("Workflow name"))
+	steps := container.NewVBox()
+	check := widget.NewLabel("")
+	check.Wrapping = fyne.TextWrapWord
+
+	var list *widget.List
+	var rebuild func()
+
+	validate := func() {
+		if err := draft.Validate(); err != nil {
+			check.Importance = widget.WarningImportance
+			check.SetText(err.Error())
+			return
+		}
+		check.Importance = widget.SuccessImportance
+		check.SetText(i18n.T("Input: %s.", dataKindLabel(draft.Input())))
+	}
+	nameEntry.OnChanged = func(s string) {
+		draft.Name = s
+		validate()
+	}
+
+	// stepCard construye el editor de un paso.
+	stepCard := func(i int) fyne.CanvasObject {
+		step := &draft.Steps[i]
+		opSelect := widget.NewSelect(refLabels, nil)
+		for j, ref := range refs {
+			if ref.Tool == step.Tool && ref.Operation.Name == step.Operation {
+				opSelect.SetSelectedIndex(j)
+			}
+		}
+		opSelect.OnChanged = func(string) {
+			ref := refs[opSelect.SelectedIndex()]
+			draft.Steps[i] = workflow.Step{Tool: ref.Tool, Operation: ref.Operation.Name}
+			rebuild()
+		}
+
+		form := widget.NewForm(widget.NewFormItem(i18n.T("Operation"), opSelect))
+		if op, err := workflow.Lookup(*step); err == nil {
+			for _, p := range op.Params {
+				key := p.Key
+				setParam := func(value string) {
+					if draft.Steps[i].Params == nil {
+						draft.Steps[i].Params = make(map[string]string)
+					}
+					draft.Steps[i].Params[key] = value
+					validate()
+				}
+				var input fyne.CanvasObject
+				if p.Options != nil {
+					sel := widget.NewSelect(p.Options(), nil)
+					sel.SetSelected(step.Params[key])
+					sel.OnChanged = setParam
+					input = sel
+				} else {
+					entry := widget.NewEntry()
+					entry.SetText(step.Params[key])
+					entry.OnChanged = setParam
+					input = entry
+				}
+				item := widget.NewFormItem(i18n.T(p.Label), input)
+				item.HintText = i18n.T(p.Description)
+				form.AppendItem(item)
+			}
+		}
+
+		move := func(to int) {
+			if to < 0 || to >= len(draft.Steps) {
+				return
+			}
+			draft.Steps[i], draft.Steps[to] = draft.Steps[to], draft.Steps[i]
+			rebuild()
+		}
+		buttons := container.NewHBox(
+			widget.NewButtonWithIcon("", theme.MoveUpIcon(), func() { move(i - 1) }),
+			widget.NewButtonWithIcon("", theme.MoveDownIcon(), func() { move(i + 1) }),
+			widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {
+				draft.Steps = append(draft.Steps[:i], draft.Steps[i+1:]...)
+				rebuild()
+			}),
+		)
+		title := widget.NewLabelWithStyle(i18n.T("Step %d", i+1), fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+		return widget.NewCard("", "", container.NewBorder(container.NewBorder(nil, nil, title, buttons), nil, nil, nil, form))
+	}
+
+	rebuild = func() {
+		steps.RemoveAll()
+		for i := range draft.Steps {
+			steps.Add(stepCard(i))
+		}
+		validate()
+	}
+
+	edit := func(i int) {
+		selected = i
+		draft = workflow.Workflow{}
+		if i >= 0 {
+			// Una copia, para que los cambios no se apliquen hasta guardar.
+			draft = workflows[i].Clone()
+		}
+		nameEntry.SetText(draft.Name)
+		rebuild()
+	}
+
+	save := func() bool {
+		if err := draft.Validate(); err != nil {
+			dialog.ShowError(err, w)
+			return false
+		}
+		for i, other := range workflows {
+			if i != selected && other.Name == draft.Name {
+				dialog.ShowError(errors.New(i18n.T("There is already a workflow called %s.", draft.Name)), w)
+				return false
+			}
+		}
+		// Se guarda una copia: draft se sigue editando después de guardar.
+		updated := append([]workflow.Workflow(nil), workflows...)
+		if selected >= 0 {
+			updated[selected] = draft.Clone()
+		} else {
+			updated = append(updated, draft.Clone())
+			selected = len(updated) - 1
+		}
+		if err := workflow.Save(updated); err != nil {
+			slog.Error("failed to save workflows", "err", err)
+			dialog.ShowError(err, w)
+			return false
+		}
+		workflows = updated
+		list.Refresh()
+		list.Select(selected)
+		services.Systray.Refresh()
+		return true
+	}
+
+	list = widget.NewList(
+		func() int { return len(workflows) },
+		func() fyne.CanvasObject { return widget.NewLabel("template") },
+		func(i widget.ListItemID, o fyne.CanvasObject) { o.(*widget.Label).SetText(workflows[i].Name) },
+	)
+	list.OnSelected = func(id widget.ListItemID) {
+		if id != selected {
+			edit(id)
+		}
+	}
+
+	newBtn := widget.NewButtonWithIcon(i18n.T("New"), theme.ContentAddIcon(), func() {
+		list.UnselectAll()
+		edit(-1)
+	})
+	deleteBtn := widget.NewButtonWithIcon(i18n.T("Delete"), theme.DeleteIcon(), func() {
+		if selected < 0 {
+			edit(-1)
+			return
+		}
+		dialog.ShowConfirm(i18n.T("Delete workflow"), i18n.T("Delete the workflow %s?", workflows[selected].Name), func(ok bool) {
+			if !ok {
+				return
+			}
+			updated := append(append([]workflow.Workflow(nil), workflows[:selected]...), workflows[selected+1:]...)
+			if err := workflow.Save(updated); err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			workflows = updated
+			list.UnselectAll()
+			list.Refresh()
+			edit(-1)
+			services.Systray.Refresh()
+		}, w)
+	})
+
+	addStepBtn := widget.NewButtonWithIcon(i18n.T("Add step"), theme.ContentAddIcon(), func() {
+		step := workflow.Step{}
+		if len(refs) > 0 {
+			step = workflow.Step{Tool: refs[0].Tool, Operation: refs[0].Operation.Name}
+		}
+		draft.Steps = append(draft.Steps, step)
+		rebuild()
+	})
+	saveBtn := widget.NewButtonWithIcon(i18n.T("Save"), theme.DocumentSaveIcon(), func() { save() })
+	runBtn := widget.NewButtonWithIcon(i18n.T("Run"), theme.MediaPlayIcon(), func() {
+		if save() {
+			runWorkflow(w, services, draft.Clone())
+		}
+	})
+	runBtn.Importance = widget.HighImportance
+
+	editor := container.NewBorder(
+		container.NewVBox(widget.NewForm(widget.NewFormItem(i18n.T("Name"), nameEntry)), widget.NewSeparator()),
+		container.NewVBox(check, container.NewHBox(addStepBtn, layout.NewSpacer(), saveBtn, runBtn)),
+		nil, nil,
+		container.NewVScroll(steps),
+	)
+	left := container.NewBorder(nil, container.NewHBox(newBtn, deleteBtn), nil, nil, list)
+	split := container.NewHSplit(left, editor)
+	split.Offset = 0.25
+	w.SetContent(split)
+
+	edit(-1)
+	for i, wf := range workflows {
+		if wf.Name == name {
+			list.Select(i)
+		}
+	}
+	w.Show()
+}
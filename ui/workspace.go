@@ -0,0 +1,143 @@
+package ui
+
+import (
+	"encoding/json"
+	"log/slog"
+	"maps"
+	"os"
+	"path/filepath"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"github.com/Lec7ral/MultiTool/i18n"
+	"github.com/Lec7ral/MultiTool/logging"
+	"github.com/Lec7ral/MultiTool/settings"
+	"github.com/Lec7ral/MultiTool/tools"
+	"github.com/Lec7ral/MultiTool/workspace"
+)
+
+// captureState guarda en l.toolState el estado de las herramientas cargadas que
+// implementan tools.StateSaver. Las que no están cargadas conservan el último
+// estado conocido, para que descargar una herramienta no pierda su trabajo.
+func (l *AppLayout) captureState() {
+	for _, d := range l.registry.GetAllDescriptors() {
+		name := d.Name
+		tool, loaded := l.registry.Loaded(name)
+		saver, ok := tool.(tools.StateSaver)
+		if !loaded || !ok || l.failed[name] != nil {
+			continue
+		}
+		var state json.RawMessage
+		var err error
+		if perr := tools.Safely(name, func() { state, err = saver.SaveState() }); perr != nil {
+			err = perr
+		}
+		if err != nil {
+			slog.Error("failed to save tool state", logging.ToolKey, name, "err", err)
+			continue
+		}
+		l.toolState[name] = state
+	}
+}
+
+// currentWorkspace devuelve el espacio de trabajo con el estado actual.
+func (l *AppLayout) currentWorkspace() workspace.Workspace {
+	l.captureState()
+	return workspace.Workspace{ActiveTool: l.active, Tools: maps.Clone(l.toolState)}
+}
+
+// applyWorkspace devuelve a cada herramienta de ws su estado, creándola si hace
+// falta, y muestra la herramienta que estaba activa.
+func (l *AppLayout) applyWorkspace(ws workspace.Workspace) {
+	for name, state := range ws.Tools {
+		if l.failed[name] != nil {
+			continue
+		}
+		tool, err := l.registry.Load(name)
+		if err != nil {
+			// Por ejemplo, una herramienta que ya no existe.
+			slog.Warn("cannot restore tool state", logging.ToolKey, name, "err", err)
+			continue
+		}
+		saver, ok := tool.(tools.StateSaver)
+		if !ok {
+			continue
+		}
+		if perr := tools.Safely(name, func() { err = saver.RestoreState(state) }); perr != nil {
+			err = perr
+		}
+		if err != nil {
+			slog.Error("failed to restore tool state", logging.ToolKey, name, "err", err)
+			continue
+		}
+		l.toolState[name] = state
+	}
+	if ws.ActiveTool != "" {
+		l.SelectTool(ws.ActiveTool)
+	}
+}
+
+// SaveSession guarda la sesión para recuperarla la próxima vez. Se llama
+// periódicamente, al cerrar la ventana y al salir.
+func (l *AppLayout) SaveSession() {
+	if err := workspace.Save(workspace.SessionPath, l.currentWorkspace()); err != nil {
+		slog.Error("failed to save session", "err", err)
+	}
+}
+
+// restoreSession recupera la sesión anterior, si el usuario no lo ha desactivado.
+func (l *AppLayout) restoreSession() {
+	if !l.services.Settings.Section(settings.AppSection).Bool(SettingRestoreSession, true) {
+		return
+	}
+	ws, err := workspace.Load(workspace.SessionPath)
+	if os.IsNotExist(err) {
+		return
+	}
+	if err != nil {
+		slog.Error("failed to load session", "err", err)
+		return
+	}
+	l.applyWorkspace(ws)
+}
+
+// SaveWorkspace pide un archivo y guarda en él el espacio de trabajo.
+func (l *AppLayout) SaveWorkspace() {
+	ws := l.currentWorkspace()
+	d := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil || writer == nil {
+			return
+		}
+		writer.Close()
+		path := localPath(writer.URI())
+		if err := workspace.Save(path, ws); err != nil {
+			dialog.ShowError(err, l.window)
+			return
+		}
+		l.status.SetStatus(i18n.T("Workspace saved to %s", path))
+	}, l.window)
+	d.SetFileName("workspace" + workspace.Extension)
+	d.SetFilter(storage.NewExtensionFileFilter([]string{workspace.Extension}))
+	d.Show()
+}
+
+// OpenWorkspace pide un archivo de espacio de trabajo y lo abre.
+func (l *AppLayout) OpenWorkspace() {
+	d := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil || reader == nil {
+			return
+		}
+		reader.Close()
+		path := localPath(reader.URI())
+		ws, err := workspace.Load(path)
+		if err != nil {
+			dialog.ShowError(err, l.window)
+			return
+		}
+		l.applyWorkspace(ws)
+		l.status.SetStatus(i18n.T("Workspace %s opened", filepath.Base(path)))
+	}, l.window)
+	d.SetFilter(storage.NewExtensionFileFilter([]string{workspace.Extension}))
+	d.Show()
+}
@@ -0,0 +1,99 @@
+// Package undo implementa un historial de deshacer/rehacer basado en comandos:
+// cada cambio que el usuario puede deshacer se registra como un Command con la
+// función que lo aplica y la que lo revierte.
+//
+// Las herramientas reciben un Stack en tools.ToolContext.Undo; Ctrl+Z y Ctrl+Y
+// deshacen y rehacen en la herramienta visible. Un Stack no es seguro para usarlo
+// desde varias goroutines: está pensado para la goroutine de la interfaz.
+package undo
+
+// DefaultLimit es el número de cambios que se recuerdan por defecto.
+const DefaultLimit = 100
+
+// Command es un cambio que puede deshacerse. Do lo aplica (también al rehacer) y
+// Undo lo revierte; los dos deben dejar la interfaz actualizada.
+type Command struct {
+	Title string // Lo que hace el cambio, ya traducido, p.ej. "Remove a.pdf".
+	Do    func()
+	Undo  func()
+}
+
+// Stack es el historial de cambios de una herramienta o ventana.
+type Stack struct {
+	done      []Command
+	undone    []Command
+	limit     int
+	listeners map[int]func()
+	nextID    int
+}
+
+// NewStack crea un historial que recuerda como mucho limit cambios.
+func NewStack(limit int) *Stack {
+	return &Stack{limit: limit, listeners: make(map[int]func())}
+}
+
+// Do aplica c y lo añade al historial. Los cambios deshechos hasta ahora ya no
+// pueden rehacerse.
+func (s *Stack) Do(c Command) {
+	c.Do()
+	s.done = append(s.done, c)
+	if len(s.done) > s.limit {
+		s.done = s.done[len(s.done)-s.limit:]
+	}
+	s.undone = nil
+	s.notify()
+}
+
+// Undo deshace el último cambio y lo devuelve. ok es false si no había ninguno.
+func (s *Stack) Undo() (c Command, ok bool) {
+	if len(s.done) == 0 {
+		return Command{}, false
+	}
+	c = s.done[len(s.done)-1]
+	s.done = s.done[:len(s.done)-1]
+	c.Undo()
+	s.undone = append(s.undone, c)
+	s.notify()
+	return c, true
+}
+
+// Redo vuelve a aplicar el último cambio deshecho y lo devuelve. ok es false si
+// no había ninguno.
+func (s *Stack) Redo() (c Command, ok bool) {
+	if len(s.undone) == 0 {
+		return Command{}, false
+	}
+	c = s.undone[len(s.undone)-1]
+	s.undone = s.undone[:len(s.undone)-1]
+	c.Do()
+	s.done = append(s.done, c)
+	s.notify()
+	return c, true
+}
+
+// CanUndo y CanRedo indican si hay algo que deshacer o rehacer.
+func (s *Stack) CanUndo() bool { return len(s.done) > 0 }
+func (s *Stack) CanRedo() bool { return len(s.undone) > 0 }
+
+// Clear vacía el historial, por ejemplo cuando los datos se sustituyen por otros
+// y los cambios anteriores ya no tienen sentido.
+func (s *Stack) Clear() {
+	s.done, s.undone = nil, nil
+	s.notify()
+}
+
+// OnChange registra fn para que se llame cada vez que el historial cambia, por
+// ejemplo para activar o desactivar los botones de deshacer. Devuelve la función
+// que la quita.
+func (s *Stack) OnChange(fn func()) (remove func()) {
+	id := s.nextID
+	s.nextID++
+	s.listeners[id] = fn
+	return func() { delete(s.listeners, id) }
+}
+
+func (s *Stack) notify() {
+	for _, fn := range s.listeners {
+		fn()
+	}
+}
@@ -0,0 +1,167 @@
+package workflow
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Lec7ral/MultiTool/i18n"
+	"github.com/Lec7ral/MultiTool/jobs"
+	"github.com/Lec7ral/MultiTool/tools"
+)
+
+// Commands son los subcomandos "workflow" de la línea de comandos.
+var Commands = []tools.Command{
+	{
+		Group:   "workflow",
+		Name:    "list",
+		Summary: "List the saved workflows",
+		Run:     runListCommand,
+	},
+	{
+		Group:   "workflow",
+		Name:    "operations",
+		Summary: "List the operations that workflow steps can use",
+		Run:     runOperationsCommand,
+	},
+	{
+		Group:   "workflow",
+		Name:    "run",
+		Usage:   "NAME [-text TEXT] [file...]",
+		Summary: "Run a saved workflow",
+		Run:     runRunCommand,
+	},
+}
+
+// Describe resume los pasos de w, p.ej. "PDF Merger: Merge PDFs → PDF Merger: Count pages".
+func (w Workflow) Describe() string {
+	parts := make([]string, len(w.Steps))
+	for i, step := range w.Steps {
+		title := step.Operation
+		if op, err := Lookup(step); err == nil {
+			title = i18n.T(op.Title)
+		}
+		parts[i] = i18n.T(step.Tool) + ": " + title
+	}
+	return strings.Join(parts, " → ")
+}
+
+// workflowList es la salida de "workflow list".
+type workflowList []Workflow
+
+func (l workflowList) String() string {
+	var b strings.Builder
+	for _, w := range l {
+		fmt.Fprintf(&b, "%s\t%s\n", w.Name, w.Describe())
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// operationInfo es una operación en la salida de "workflow operations".
+type operationInfo struct {
+	Tool      string   `json:"tool"`
+	Operation string   `json:"operation"`
+	Title     string   `json:"title"`
+	Input     string   `json:"input"`
+	Output    string   `json:"output"`
+	Params    []string `json:"params,omitempty"`
+}
+
+type operationList []operationInfo
+
+func (l operationList) String() string {
+	var b strings.Builder
+	for _, op := range l {
+		fmt.Fprintf(&b, "%s/%s\t%s → %s\t%s", op.Tool, op.Operation, op.Input, op.Output, op.Title)
+		if len(op.Params) > 0 {
+			fmt.Fprintf(&b, " (%s)", strings.Join(op.Params, ", "))
+		}
+		b.WriteString("\n")
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func runListCommand(ctx context.Context, args []string) (any, error) {
+	if len(args) != 0 {
+		return nil, fmt.Errorf("%w: workflow list takes no arguments", tools.ErrUsage)
+	}
+	workflows, err := Load()
+	if err != nil {
+		return nil, err
+	}
+	return workflowList(workflows), nil
+}
+
+func runOperationsCommand(ctx context.Context, args []string) (any, error) {
+	if len(args) != 0 {
+		return nil, fmt.Errorf("%w: workflow operations takes no arguments", tools.ErrUsage)
+	}
+	var result operationList
+	for _, ref := range Operations() {
+		info := operationInfo{
+			Tool:      ref.Tool,
+			Operation: ref.Operation.Name,
+			Title:     i18n.T(ref.Operation.Title),
+			Input:     kindName(ref.Operation.Input),
+			Output:    kindName(ref.Operation.Output),
+		}
+		for _, p := range ref.Operation.Params {
+			info.Params = append(info.Params, p.Key)
+		}
+		result = append(result, info)
+	}
+	return result, nil
+}
+
+// runResult es la salida de "workflow run": la salida del último paso que produjo algo.
+type runResult struct {
+	Workflow string   `json:"workflow"`
+	Files    []string `json:"files,omitempty"`
+	Text     string   `json:"text,omitempty"`
+}
+
+func (r runResult) String() string {
+	if len(r.Files) > 0 {
+		return strings.Join(r.Files, "\n")
+	}
+	if r.Text != "" {
+		return r.Text
+	}
+	return i18n.T("Workflow '%s' finished.", r.Workflow)
+}
+
+func runRunCommand(ctx context.Context, args []string) (any, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("%w: expected a workflow name", tools.ErrUsage)
+	}
+	name := args[0]
+	fs := flag.NewFlagSet("workflow run", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	text := fs.String("text", "", "input text")
+	if err := fs.Parse(args[1:]); err != nil {
+		return nil, fmt.Errorf("%w: %v", tools.ErrUsage, err)
+	}
+
+	w, err := Find(name)
+	if err != nil {
+		return nil, err
+	}
+	var in tools.Data
+	switch w.Input() {
+	case tools.Files:
+		if fs.NArg() == 0 {
+			return nil, fmt.Errorf("%w: workflow %q needs input files", tools.ErrUsage, name)
+		}
+		in = tools.FilesData(fs.Args()...)
+	case tools.Text:
+		in = tools.TextData(*text)
+	}
+
+	out, err := w.Run(ctx, in, jobs.Discard)
+	if err != nil {
+		return nil, err
+	}
+	return runResult{Workflow: w.Name, Files: out.Files, Text: out.Text}, nil
+}
@@ -0,0 +1,253 @@
+// Package workflow encadena operaciones de las herramientas en flujos de trabajo
+// con nombre, por ejemplo "fusionar estos PDFs y contar las páginas del
+// resultado" o "aplicar el perfil de red Wired y después fusionar".
+//
+// Las operaciones las aportan las herramientas en tools.ToolDescriptor.Operations.
+// Cada una declara el tipo de datos que recibe y el que produce (archivos, texto
+// o nada), y la salida de un paso es la entrada del siguiente. Los flujos se
+// guardan en workflows.json en el directorio de configuración y se ejecutan
+// desde la interfaz, la bandeja del sistema o la línea de comandos.
+package workflow
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/Lec7ral/MultiTool/config"
+	"github.com/Lec7ral/MultiTool/i18n"
+	"github.com/Lec7ral/MultiTool/jobs"
+	"github.com/Lec7ral/MultiTool/tools"
+)
+
+//go:embed locales
+var locales embed.FS
+
+func init() {
+	if err := i18n.AddCatalogFS(locales, "locales"); err != nil {
+		slog.Error("failed to load workflow translations", "err", err)
+	}
+}
+
+// JobTool es el nombre con el que las ejecuciones aparecen en el panel de tareas.
+const JobTool = "Workflows"
+
+// Step es un paso de un flujo: una operación de una herramienta y sus parámetros.
+type Step struct {
+	Tool      string            `json:"tool"`
+	Operation string            `json:"operation"`
+	Params    map[string]string `json:"params,omitempty"`
+}
+
+// Workflow es un flujo de trabajo con nombre.
+type Workflow struct {
+	Name  string `json:"name"`
+	Steps []Step `json:"steps"`
+}
+
+// Clone devuelve una copia de w que no comparte sus pasos ni sus parámetros, de
+// modo que editar una no cambia la otra.
+func (w Workflow) Clone() Workflow {
+	clone := Workflow{Name: w.Name}
+	for _, s := range w.Steps {
+		params := make(map[string]string, len(s.Params))
+		for k, v := range s.Params {
+			params[k] = v
+		}
+		clone.Steps = append(clone.Steps, Step{Tool: s.Tool, Operation: s.Operation, Params: params})
+	}
+	return clone
+}
+
+var filePath = config.Path("workflows.json")
+
+// Load lee los flujos guardados. Si el archivo no existe, no hay ninguno.
+func Load() ([]Workflow, error) {
+	raw, err := os.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var workflows []Workflow
+	if err := json.Unmarshal(raw, &workflows); err != nil {
+		return nil, fmt.Errorf("parse workflows.json: %w", err)
+	}
+	return workflows, nil
+}
+
+// Save guarda workflows, sustituyendo los que hubiera. Escribe primero un archivo
+// temporal y lo renombra, para no perder los flujos si se interrumpe a medias.
+func Save(workflows []Workflow) error {
+	raw, err := json.MarshalIndent(workflows, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := filePath + ".tmp"
+	if err := os.WriteFile(tmp, raw, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filePath)
+}
+
+// Find devuelve el flujo guardado con ese nombre. Si no hay ninguno, el error
+// envuelve tools.ErrNotFound.
+func Find(name string) (Workflow, error) {
+	workflows, err := Load()
+	if err != nil {
+		return Workflow{}, err
+	}
+	for _, w := range workflows {
+		if w.Name == name {
+			return w, nil
+		}
+	}
+	return Workflow{}, fmt.Errorf("workflow %q %w", name, tools.ErrNotFound)
+}
+
+// Ref identifica una operación disponible: la herramienta que la aporta y la
+// operación en sí.
+type Ref struct {
+	Tool      string
+	Operation tools.Operation
+}
+
+// Operations devuelve las operaciones de todas las herramientas del catálogo, en
+// orden de registro.
+func Operations() []Ref {
+	var refs []Ref
+	for _, d := range tools.Descriptors() {
+		for _, op := range d.Operations {
+			refs = append(refs, Ref{Tool: d.Name, Operation: op})
+		}
+	}
+	return refs
+}
+
+// Lookup devuelve la operación de un paso.
+func Lookup(step Step) (tools.Operation, error) {
+	for _, ref := range Operations() {
+		if ref.Tool == step.Tool && ref.Operation.Name == step.Operation {
+			return ref.Operation, nil
+		}
+	}
+	return tools.Operation{}, fmt.Errorf("unknown operation %s/%s", step.Tool, step.Operation)
+}
+
+// Input devuelve el tipo de datos que hay que dar al flujo para ejecutarlo: el
+// que espera el primer paso que necesita datos antes de que otro los produzca.
+func (w Workflow) Input() tools.DataKind {
+	for _, step := range w.Steps {
+		op, err := Lookup(step)
+		if err != nil {
+			return tools.NoData
+		}
+		if op.Input != tools.NoData {
+			return op.Input
+		}
+		if op.Output != tools.NoData {
+			return tools.NoData
+		}
+	}
+	return tools.NoData
+}
+
+// Validate comprueba que el flujo tiene nombre y pasos, que sus operaciones
+// existen y tienen los parámetros obligatorios, y que cada paso recibe el tipo
+// de datos que espera.
+func (w Workflow) Validate() error {
+	if strings.TrimSpace(w.Name) == "" {
+		return errors.New("the workflow has no name")
+	}
+	if len(w.Steps) == 0 {
+		return errors.New("the workflow has no steps")
+	}
+	kind := w.Input()
+	for i, step := range w.Steps {
+		op, err := Lookup(step)
+		if err != nil {
+			return fmt.Errorf("step %d: %w", i+1, err)
+		}
+		for _, p := range op.Params {
+			if p.Required && strings.TrimSpace(step.Params[p.Key]) == "" {
+				return fmt.Errorf("step %d (%s): %q is required", i+1, op.Title, p.Label)
+			}
+		}
+		if op.Input != tools.NoData && op.Input != kind {
+			return fmt.Errorf("step %d (%s) needs %s but gets %s", i+1, op.Title, kindName(op.Input), kindName(kind))
+		}
+		if op.Output != tools.NoData {
+			kind = op.Output
+		}
+	}
+	return nil
+}
+
+// Run ejecuta los pasos en orden, pasando a cada uno la salida del anterior, y
+// devuelve la salida final. Se detiene en el primer error o cuando ctx se cancela.
+func (w Workflow) Run(ctx context.Context, in tools.Data, r jobs.Reporter) (tools.Data, error) {
+	if err := w.Validate(); err != nil {
+		return tools.Data{}, err
+	}
+	if kind := w.Input(); kind != tools.NoData && in.Kind != kind {
+		return tools.Data{}, fmt.Errorf("the workflow needs %s as input", kindName(kind))
+	}
+
+	data := in
+	for i, step := range w.Steps {
+		if err := ctx.Err(); err != nil {
+			return data, err
+		}
+		op, _ := Lookup(step) // Validate ya comprobó que existe.
+		r.Logf("%d/%d: %s", i+1, len(w.Steps), op.Title)
+
+		var out tools.Data
+		var err error
+		if perr := tools.Safely(step.Tool, func() {
+			out, err = op.Run(ctx, data, step.Params, stepReporter{r: r, step: i, steps: len(w.Steps)})
+		}); perr != nil {
+			err = perr
+		}
+		if err != nil {
+			return data, fmt.Errorf("step %d (%s): %w", i+1, op.Title, err)
+		}
+		if op.Output != tools.NoData {
+			out.Kind = op.Output
+			data = out
+		}
+	}
+	r.SetProgress(1)
+	return data, nil
+}
+
+// kindName es el nombre de un tipo de datos en los mensajes de error.
+func kindName(kind tools.DataKind) string {
+	if kind == tools.NoData {
+		return "nothing"
+	}
+	return string(kind)
+}
+
+// stepReporter convierte el progreso de un paso en progreso del flujo completo.
+type stepReporter struct {
+	r           jobs.Reporter
+	step, steps int
+}
+
+func (s stepReporter) SetProgress(fraction float64) {
+	if fraction < 0 {
+		s.r.SetProgress(fraction)
+		return
+	}
+	s.r.SetProgress((float64(s.step) + min(fraction, 1)) / float64(s.steps))
+}
+
+func (s stepReporter) Logf(format string, args ...any) {
+	s.r.Logf(format, args...)
+}
@@ -0,0 +1,192 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Lec7ral/MultiTool/jobs"
+	"github.com/Lec7ral/MultiTool/tools"
+)
+
+// fakeTool es el nombre de la herramienta falsa cuyas operaciones usan las
+// pruebas. Se añade al catálogo global igual que las herramientas reales.
+const fakeTool = "Fake"
+
+var errFake = errors.New("fake failure")
+
+func init() {
+	tools.Register(tools.ToolDescriptor{Name: fakeTool, Category: "Test", Operations: []tools.Operation{
+		{Name: "list", Title: "List", Output: tools.Files,
+			Run: func(ctx context.Context, in tools.Data, params map[string]string, r jobs.Reporter) (tools.Data, error) {
+				return tools.FilesData("a.pdf", "b.pdf"), nil
+			}},
+		{Name: "join", Title: "Join", Input: tools.Files, Output: tools.Text,
+			Params: []tools.Param{{Key: "sep", Label: "Separator", Required: true}},
+			Run: func(ctx context.Context, in tools.Data, params map[string]string, r jobs.Reporter) (tools.Data, error) {
+				return tools.TextData(strings.Join(in.Files, params["sep"])), nil
+			}},
+		{Name: "upper", Title: "Upper", Input: tools.Text, Output: tools.Text,
+			Run: func(ctx context.Context, in tools.Data, params map[string]string, r jobs.Reporter) (tools.Data, error) {
+				return tools.TextData(strings.ToUpper(in.Text)), nil
+			}},
+		{Name: "noop", Title: "Noop",
+			Run: func(ctx context.Context, in tools.Data, params map[string]string, r jobs.Reporter) (tools.Data, error) {
+				return tools.Data{}, nil
+			}},
+		{Name: "fail", Title: "Fail",
+			Run: func(ctx context.Context, in tools.Data, params map[string]string, r jobs.Reporter) (tools.Data, error) {
+				return tools.Data{}, errFake
+			}},
+		{Name: "panic", Title: "Panic",
+			Run: func(ctx context.Context, in tools.Data, params map[string]string, r jobs.Reporter) (tools.Data, error) {
+				panic("boom")
+			}},
+	}})
+}
+
+// fakeStep es un paso con una operación de la herramienta falsa.
+func fakeStep(op string, params ...string) Step {
+	s := Step{Tool: fakeTool, Operation: op}
+	if len(params) > 0 {
+		s.Params = map[string]string{}
+		for i := 0; i+1 < len(params); i += 2 {
+			s.Params[params[i]] = params[i+1]
+		}
+	}
+	return s
+}
+
+// TestCloneEditAfterSave reproduce el editor de flujos: guarda una copia del
+// borrador y lo sigue editando. Lo guardado no debe cambiar.
+func TestCloneEditAfterSave(t *testing.T) {
+	draft := Workflow{Name: "Merge and count", Steps: []Step{
+		{Tool: "PDF Merger", Operation: "merge", Params: map[string]string{"output": "a.pdf"}},
+		{Tool: "Text Tools", Operation: "count"},
+		{Tool: "Network Switcher", Operation: "apply", Params: map[string]string{"profile": "Wired"}},
+	}}
+	saved := []Workflow{draft.Clone()}
+	want := []Workflow{draft.Clone()}
+
+	draft.Name = "Renamed"
+	draft.Steps[0].Params["output"] = "b.pdf"
+	draft.Steps = append(draft.Steps[:1], draft.Steps[2:]...) // Borra el segundo paso.
+	draft.Steps[0], draft.Steps[1] = draft.Steps[1], draft.Steps[0]
+
+	if !reflect.DeepEqual(saved, want) {
+		t.Errorf("saved workflows changed after editing the draft:\n got %+v\nwant %+v", saved, want)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		wf      Workflow
+		wantErr string // Fragmento del error; vacío si el flujo es válido.
+	}{
+		{"valid chain", Workflow{Name: "w", Steps: []Step{fakeStep("list"), fakeStep("join", "sep", ","), fakeStep("upper")}}, ""},
+		{"input from caller", Workflow{Name: "w", Steps: []Step{fakeStep("upper")}}, ""},
+		{"no-output step passes data through", Workflow{Name: "w", Steps: []Step{fakeStep("list"), fakeStep("noop"), fakeStep("join", "sep", ",")}}, ""},
+		{"no name", Workflow{Name: " ", Steps: []Step{fakeStep("list")}}, "no name"},
+		{"no steps", Workflow{Name: "w"}, "no steps"},
+		{"unknown tool", Workflow{Name: "w", Steps: []Step{fakeStep("list"), {Tool: "Missing", Operation: "list"}}}, "step 2: unknown operation Missing/list"},
+		{"unknown operation", Workflow{Name: "w", Steps: []Step{fakeStep("missing")}}, "step 1: unknown operation Fake/missing"},
+		{"missing required param", Workflow{Name: "w", Steps: []Step{fakeStep("list"), fakeStep("join")}}, `step 2 (Join): "Separator" is required`},
+		{"blank required param", Workflow{Name: "w", Steps: []Step{fakeStep("list"), fakeStep("join", "sep", "  ")}}, `"Separator" is required`},
+		{"kind mismatch", Workflow{Name: "w", Steps: []Step{fakeStep("list"), fakeStep("upper")}}, "step 2 (Upper) needs text but gets files"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.wf.Validate()
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("Validate() = %v, want nil", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("Validate() = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestInput(t *testing.T) {
+	tests := []struct {
+		name  string
+		steps []Step
+		want  tools.DataKind
+	}{
+		{"no steps", nil, tools.NoData},
+		{"first step produces data", []Step{fakeStep("list"), fakeStep("join", "sep", ",")}, tools.NoData},
+		{"first step needs files", []Step{fakeStep("join", "sep", ","), fakeStep("upper")}, tools.Files},
+		{"first step needs text", []Step{fakeStep("upper")}, tools.Text},
+		{"skips steps without data", []Step{fakeStep("noop"), fakeStep("upper")}, tools.Text},
+		{"unknown operation", []Step{fakeStep("missing"), fakeStep("upper")}, tools.NoData},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (Workflow{Name: "w", Steps: tt.steps}).Input(); got != tt.want {
+				t.Errorf("Input() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		steps   []Step
+		in      tools.Data
+		want    tools.Data
+		wantErr error  // Error que debe envolver el resultado, si lo hay.
+		errText string // Fragmento del mensaje de error.
+	}{
+		{name: "chain", steps: []Step{fakeStep("list"), fakeStep("noop"), fakeStep("join", "sep", "+"), fakeStep("upper")},
+			want: tools.TextData("A.PDF+B.PDF")},
+		{name: "caller input", steps: []Step{fakeStep("upper")}, in: tools.TextData("hi"), want: tools.TextData("HI")},
+		{name: "wrong input", steps: []Step{fakeStep("upper")}, in: tools.FilesData("a.pdf"), errText: "needs text as input"},
+		{name: "invalid workflow", steps: []Step{fakeStep("missing")}, errText: "unknown operation"},
+		{name: "canceled", ctx: canceled, steps: []Step{fakeStep("list")}, wantErr: context.Canceled},
+		{name: "step error", steps: []Step{fakeStep("list"), fakeStep("fail")}, wantErr: errFake, errText: "step 2 (Fail)"},
+		{name: "step panic", steps: []Step{fakeStep("noop"), fakeStep("panic")}, errText: "step 2 (Panic): Fake crashed: boom"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := tt.ctx
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			got, err := Workflow{Name: "w", Steps: tt.steps}.Run(ctx, tt.in, jobs.Discard)
+			if tt.wantErr == nil && tt.errText == "" {
+				if err != nil {
+					t.Fatalf("Run() error = %v", err)
+				}
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("Run() = %+v, want %+v", got, tt.want)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("Run() error = nil, want an error")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Run() error = %v, want it to wrap %v", err, tt.wantErr)
+			}
+			if !strings.Contains(err.Error(), tt.errText) {
+				t.Errorf("Run() error = %v, want it to contain %q", err, tt.errText)
+			}
+		})
+	}
+}
+
+func TestRunPanicIsPanicError(t *testing.T) {
+	_, err := Workflow{Name: "w", Steps: []Step{fakeStep("panic")}}.Run(context.Background(), tools.Data{}, jobs.Discard)
+	var perr *tools.PanicError
+	if !errors.As(err, &perr) || perr.Tool != fakeTool {
+		t.Errorf("Run() error = %v, want a *tools.PanicError for %q", err, fakeTool)
+	}
+}
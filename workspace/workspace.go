@@ -0,0 +1,63 @@
+// Package workspace define los espacios de trabajo: archivos JSON con el estado
+// de las herramientas (la lista del PDF Merger con su orden y sus páginas, por
+// ejemplo) que se guardan y se abren desde la interfaz. La sesión actual se
+// guarda sola en session.json para recuperarla al arrancar.
+//
+// Cada herramienta aporta su estado implementando tools.StateSaver; el formato
+// de ese estado es cosa suya.
+package workspace
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/Lec7ral/MultiTool/config"
+)
+
+// Version es la versión del formato. Los archivos de versiones posteriores no se abren.
+const Version = 1
+
+// Extension es la extensión que proponen los diálogos de guardar y abrir.
+const Extension = ".json"
+
+// SessionPath es el archivo donde se guarda la sesión automáticamente.
+var SessionPath = config.Path("session.json")
+
+// Workspace es el contenido de un archivo de espacio de trabajo.
+type Workspace struct {
+	Version    int                        `json:"version"`
+	ActiveTool string                     `json:"activeTool,omitempty"`
+	Tools      map[string]json.RawMessage `json:"tools"` // Estado de cada herramienta, por nombre.
+}
+
+// Load lee el espacio de trabajo de path.
+func Load(path string) (Workspace, error) {
+	var ws Workspace
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return ws, err
+	}
+	if err := json.Unmarshal(raw, &ws); err != nil {
+		return ws, fmt.Errorf("parse %s: %w", path, err)
+	}
+	if ws.Version > Version {
+		return ws, fmt.Errorf("%s was saved by a newer version of MultiTool", path)
+	}
+	return ws, nil
+}
+
+// Save escribe ws en path. Escribe primero en un archivo temporal para no dejar
+// el anterior a medias si algo falla.
+func Save(path string, ws Workspace) error {
+	ws.Version = Version
+	raw, err := json.MarshalIndent(ws, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, raw, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}